@@ -0,0 +1,176 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryDelta is how long before expiry a cached token is refreshed, so
+// that a request never leaves with a token that expires in flight.
+const tokenExpiryDelta = 60 * time.Second
+
+// orgIDHeader asks the token endpoint for a token scoped to a child
+// organization when authenticating with provider console credentials.
+const orgIDHeader = "X-Umbrella-OrgId"
+
+// tokenSource fetches OAuth2 client-credentials tokens from the Umbrella
+// token endpoint and caches them until shortly before they expire. A non-zero
+// orgID scopes the tokens to that organization.
+type tokenSource struct {
+	tokenURL  string
+	apikey    string
+	apisecret string
+	orgID     int64
+	client    *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+type tokenResponse struct {
+	TokenType   string `json:"token_type"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func newTokenSource(host, apikey, apisecret string, orgID int64, client *http.Client) *tokenSource {
+	return &tokenSource{
+		tokenURL:  strings.TrimSuffix(host, "/") + "/auth/v2/token",
+		apikey:    apikey,
+		apisecret: apisecret,
+		orgID:     orgID,
+		client:    client,
+	}
+}
+
+// Token returns the cached token, fetching a new one if it is missing or
+// about to expire.
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Add(tokenExpiryDelta).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	tr, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = tr.AccessToken
+	s.expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+
+	return s.token, nil
+}
+
+// Invalidate drops token from the cache so that the next call to Token
+// fetches a new one. Tokens that were already replaced are left alone.
+func (s *tokenSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+func (s *tokenSource) fetch(ctx context.Context) (*tokenResponse, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(s.apikey, s.apisecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.orgID != 0 {
+		req.Header.Set(orgIDHeader, strconv.FormatInt(s.orgID, 10))
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch access token: %w", newAPIError(res.StatusCode, string(body), res.Header))
+	}
+
+	tr := tokenResponse{}
+	err = json.Unmarshal(body, &tr)
+	if err != nil {
+		return nil, err
+	}
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("unable to fetch access token, response did not contain one")
+	}
+
+	return &tr, nil
+}
+
+// authTransport adds the current bearer token to every request. A request
+// rejected with 401 is retried once with a freshly fetched token.
+type authTransport struct {
+	tokens *tokenSource
+	base   http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.tokens.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.base.RoundTrip(withBearer(req, token))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	// The body of the first attempt is consumed, so only requests that can
+	// rewind it are retried.
+	if req.Body != nil && req.GetBody == nil {
+		return res, nil
+	}
+
+	res.Body.Close()
+	t.tokens.Invalidate(token)
+
+	token, err = t.tokens.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	retry := withBearer(req, token)
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t.base.RoundTrip(retry)
+}
+
+// withBearer returns a copy of req carrying token, leaving the caller's
+// request untouched as the RoundTripper contract requires.
+func withBearer(req *http.Request, token string) *http.Request {
+	out := req.Clone(req.Context())
+	out.Header.Set("Authorization", "Bearer "+token)
+	return out
+}
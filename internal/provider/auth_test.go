@@ -0,0 +1,87 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAuthTransportRefreshesTokenAfterUnauthorized(t *testing.T) {
+	var issued int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v2/token", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "key" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"token_type":"bearer","access_token":"token-%d","expires_in":3600}`, n)
+	})
+	mux.HandleFunc("/deployments/v2/sites", func(w http.ResponseWriter, r *http.Request) {
+		// The first token is treated as revoked by the server.
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := newAPIClient(context.Background(), server.URL, "key", "secret", 0, defaultRetryPolicy())
+	if err != nil {
+		t.Fatalf("newAPIClient: %s", err)
+	}
+
+	req, _ := http.NewRequest("POST", server.URL+"/deployments/v2/sites", strings.NewReader(`{"name":"one"}`))
+	body, err := client.doRequest(req, nil)
+	if err != nil {
+		t.Fatalf("doRequest: %s", err)
+	}
+	if string(body) != `{"name":"one"}` {
+		t.Errorf("expected request body to be replayed, got %q", body)
+	}
+	if issued != 2 {
+		t.Errorf("expected 2 tokens to be issued, got %d", issued)
+	}
+}
+
+func TestTokenSourceCachesToken(t *testing.T) {
+	var issued int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"token_type":"bearer","access_token":"token-%d","expires_in":3600}`, n)
+	}))
+	defer server.Close()
+
+	tokens := newTokenSource(server.URL, "key", "secret", 0, server.Client())
+
+	for i := 0; i < 3; i++ {
+		token, err := tokens.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token: %s", err)
+		}
+		if token != "token-1" {
+			t.Errorf("expected cached token-1, got %s", token)
+		}
+	}
+
+	// Tokens that expire within tokenExpiryDelta are refreshed.
+	tokens.expiry = time.Now()
+	token, err := tokens.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %s", err)
+	}
+	if token != "token-2" {
+		t.Errorf("expected refreshed token-2, got %s", token)
+	}
+}
@@ -0,0 +1,198 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/olegunza/umbrella-api-go/umbrella"
+)
+
+// requestTimeout matches the timeout of clients built by umbrella.NewClient.
+// retryTransport applies it to every attempt of a request.
+const requestTimeout = 10 * time.Second
+
+// apiClient wraps the umbrella-api-go client and adds the endpoints the
+// upstream package does not implement yet. Upstream methods stay reachable
+// through the embedded client.
+//
+// Authorization is handled by the HTTP transport, which takes the current
+// token from the token source. Callers therefore pass a nil authToken.
+type apiClient struct {
+	*umbrella.Client
+	tokens *tokenSource
+	orgID  int64
+	orgs   *orgClients
+}
+
+// orgClients holds one client per organization so that every organization
+// keeps its own cached token.
+type orgClients struct {
+	host      string
+	apikey    string
+	apisecret string
+	retries   retryPolicy
+	// ctx carries the provider logger for the HTTP transports.
+	ctx context.Context
+
+	mu      sync.Mutex
+	clients map[int64]*apiClient
+}
+
+// newAPIClient builds a client for host scoped to orgID, or to the
+// organization owning the credentials when orgID is 0. It fetches the first
+// access token so that invalid credentials are reported while configuring the
+// provider. Throttled and unavailable responses are retried according to
+// retries.
+func newAPIClient(ctx context.Context, host, apikey, apisecret string, orgID int64, retries retryPolicy) (*apiClient, error) {
+	orgs := &orgClients{
+		host:      host,
+		apikey:    apikey,
+		apisecret: apisecret,
+		retries:   retries,
+		ctx:       ctx,
+		clients:   map[int64]*apiClient{},
+	}
+
+	client := orgs.get(orgID)
+
+	_, err := client.tokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (o *orgClients) get(orgID int64) *apiClient {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if client, ok := o.clients[orgID]; ok {
+		return client
+	}
+
+	base := &retryTransport{
+		policy: o.retries,
+		base:   http.DefaultTransport,
+		ctx:    o.ctx,
+	}
+	tokens := newTokenSource(o.host, o.apikey, o.apisecret, orgID, &http.Client{
+		Transport: base,
+	})
+
+	client := &apiClient{
+		Client: &umbrella.Client{
+			HostURL: o.host,
+			HTTPClient: &http.Client{
+				Transport: &authTransport{
+					tokens: tokens,
+					base:   base,
+				},
+			},
+		},
+		tokens: tokens,
+		orgID:  orgID,
+		orgs:   o,
+	}
+	o.clients[orgID] = client
+
+	return client
+}
+
+// forOrg returns the client for the organization in orgID. A null or unknown
+// orgID selects the provider organization.
+func (c *apiClient) forOrg(orgID types.Int64) *apiClient {
+	if orgID.IsNull() || orgID.IsUnknown() || orgID.ValueInt64() == c.orgID {
+		return c
+	}
+
+	return c.orgs.get(orgID.ValueInt64())
+}
+
+// orgIDValue returns the organization the client is scoped to, or null when
+// it uses the organization owning the credentials.
+func (c *apiClient) orgIDValue() types.Int64 {
+	if c.orgID == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(c.orgID)
+}
+
+// doRequest mirrors the upstream client behaviour so that errors look the same
+// regardless of which package issued the request.
+func (c *apiClient) doRequest(req *http.Request, authToken *string) ([]byte, error) {
+	if authToken != nil {
+		req.Header.Set("Authorization", "Bearer "+*authToken)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNoContent {
+		return []byte("204"), nil
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return nil, newAPIError(res.StatusCode, string(body), res.Header)
+	}
+
+	return body, nil
+}
+
+// GetTunnels - Returns list of network tunnels
+func (c *apiClient) GetTunnels(authToken *string) ([]umbrella.NetworkTunnel, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/tunnels", c.HostURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	tunnels := []umbrella.NetworkTunnel{}
+	err = json.Unmarshal(body, &tunnels)
+	if err != nil {
+		return nil, err
+	}
+
+	return tunnels, nil
+}
+
+// withContext returns a copy of the client whose requests are bound to ctx,
+// so that cancellation and the operation deadline reach the HTTP layer even
+// for upstream methods that build their requests without a context.
+func (c *apiClient) withContext(ctx context.Context) *apiClient {
+	client := *c.Client
+	httpClient := *client.HTTPClient
+	httpClient.Transport = &contextTransport{ctx: ctx, base: c.HTTPClient.Transport}
+	client.HTTPClient = &httpClient
+
+	out := *c
+	out.Client = &client
+	return &out
+}
+
+// contextTransport replaces the context of every request with ctx.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
@@ -0,0 +1,89 @@
+package umbrellaprovider
+
+import (
+	"github.com/olegunza/umbrella-api-go/umbrella"
+)
+
+// The methods below shadow the upstream client so that its errors reach the
+// resources as *apiError, like the errors of the endpoints implemented here.
+
+// GetSite - Returns a specific site
+func (c *apiClient) GetSite(siteID int64, authToken *string) (*umbrella.Site, error) {
+	site, err := c.Client.GetSite(siteID, authToken)
+	return site, wrapAPIError(err)
+}
+
+// GetSites - Returns all sites
+func (c *apiClient) GetSites(authToken *string) ([]umbrella.Site, error) {
+	sites, err := c.Client.GetSites(authToken)
+	return sites, wrapAPIError(err)
+}
+
+// CreateSite - Create new site
+func (c *apiClient) CreateSite(siteItem umbrella.Site, authToken *string) (*umbrella.Site, error) {
+	site, err := c.Client.CreateSite(siteItem, authToken)
+	return site, wrapAPIError(err)
+}
+
+// UpdateSite - Updates a site
+func (c *apiClient) UpdateSite(siteID int64, siteItem umbrella.Site, authToken *string) (*umbrella.Site, error) {
+	site, err := c.Client.UpdateSite(siteID, siteItem, authToken)
+	return site, wrapAPIError(err)
+}
+
+// DeleteSite - Deletes a site
+func (c *apiClient) DeleteSite(siteID int64, authToken *string) error {
+	return wrapAPIError(c.Client.DeleteSite(siteID, authToken))
+}
+
+// GetTunnel - Returns a specific tunnel
+func (c *apiClient) GetTunnel(tunnelID int64, authToken *string) (*umbrella.NetworkTunnel, error) {
+	tunnel, err := c.Client.GetTunnel(tunnelID, authToken)
+	return tunnel, wrapAPIError(err)
+}
+
+// CreateTunnel - Create new tunnel
+func (c *apiClient) CreateTunnel(tunnelItem umbrella.NetworkTunnel, authToken *string) (*umbrella.NetworkTunnel, error) {
+	tunnel, err := c.Client.CreateTunnel(tunnelItem, authToken)
+	return tunnel, wrapAPIError(err)
+}
+
+// UpdateTunnel - Updates a tunnel
+func (c *apiClient) UpdateTunnel(tunnelID int64, tunnelItem umbrella.NetworkTunnel, authToken *string) (*umbrella.NetworkTunnel, error) {
+	tunnel, err := c.Client.UpdateTunnel(tunnelID, tunnelItem, authToken)
+	return tunnel, wrapAPIError(err)
+}
+
+// DeleteTunnel - Deletes a tunnel
+func (c *apiClient) DeleteTunnel(tunnelID int64, authToken *string) error {
+	return wrapAPIError(c.Client.DeleteTunnel(tunnelID, authToken))
+}
+
+// GetDCs - Returns the list of Umbrella datacenters
+func (c *apiClient) GetDCs(authToken *string) (*umbrella.DCList, error) {
+	dcs, err := c.Client.GetDCs(authToken)
+	return dcs, wrapAPIError(err)
+}
+
+// GetVA - Returns a specific virtual appliance
+func (c *apiClient) GetVA(originID int64, authToken *string) (*umbrella.VA, error) {
+	va, err := c.Client.GetVA(originID, authToken)
+	return va, wrapAPIError(err)
+}
+
+// GetVAs - Returns all virtual appliances
+func (c *apiClient) GetVAs(authToken *string) ([]umbrella.VA, error) {
+	vas, err := c.Client.GetVAs(authToken)
+	return vas, wrapAPIError(err)
+}
+
+// UpdateVA - Updates a virtual appliance
+func (c *apiClient) UpdateVA(originID int64, vaItem umbrella.VA, authToken *string) (*umbrella.VA, error) {
+	va, err := c.Client.UpdateVA(originID, vaItem, authToken)
+	return va, wrapAPIError(err)
+}
+
+// DeleteVA - Deletes a virtual appliance
+func (c *apiClient) DeleteVA(originID int64, authToken *string) error {
+	return wrapAPIError(c.Client.DeleteVA(originID, authToken))
+}
@@ -0,0 +1,215 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// destinationBatchSize is the largest number of destinations the API accepts
+// in a single add or remove request.
+const destinationBatchSize = 500
+
+// destinationPageSize is the page size used when listing destinations.
+const destinationPageSize = 100
+
+type DestinationList struct {
+	Id           int64               `json:"id,omitempty"`
+	Name         string              `json:"name"`
+	Access       string              `json:"access,omitempty"`
+	IsGlobal     bool                `json:"isGlobal"`
+	BundleTypeId int64               `json:"bundleTypeId,omitempty"`
+	CreatedAt    int64               `json:"createdAt,omitempty"`
+	ModifiedAt   int64               `json:"modifiedAt,omitempty"`
+	Meta         DestinationListMeta `json:"meta,omitempty"`
+}
+
+type DestinationListMeta struct {
+	DestinationCount int64 `json:"destinationCount,omitempty"`
+}
+
+type Destination struct {
+	Id          string `json:"id,omitempty"`
+	Destination string `json:"destination"`
+	Type        string `json:"type,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+type destinationListResponse struct {
+	Data DestinationList `json:"data"`
+}
+
+type destinationsResponse struct {
+	Data []Destination `json:"data"`
+}
+
+// GetDestinationList - Returns a specific destination list
+func (c *apiClient) GetDestinationList(listID int64, authToken *string) (*DestinationList, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/policies/v2/destinationlists/%d", c.HostURL, listID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	response := destinationListResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.Data, nil
+}
+
+// CreateDestinationList - Create new destination list without destinations
+func (c *apiClient) CreateDestinationList(listItem DestinationList, authToken *string) (*DestinationList, error) {
+	rb, err := json.Marshal(listItem)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/policies/v2/destinationlists", c.HostURL), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	response := destinationListResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.Data, nil
+}
+
+// RenameDestinationList - Updates the name of a destination list
+func (c *apiClient) RenameDestinationList(listID int64, name string, authToken *string) (*DestinationList, error) {
+	rb, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/policies/v2/destinationlists/%d", c.HostURL, listID), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	response := destinationListResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.Data, nil
+}
+
+// DeleteDestinationList - Deletes a destination list
+func (c *apiClient) DeleteDestinationList(listID int64, authToken *string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/policies/v2/destinationlists/%d", c.HostURL, listID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
+
+// GetDestinations - Returns every destination of a destination list
+func (c *apiClient) GetDestinations(listID int64, authToken *string) ([]Destination, error) {
+	destinations := []Destination{}
+
+	for page := 1; ; page++ {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/policies/v2/destinationlists/%d/destinations?page=%d&limit=%d", c.HostURL, listID, page, destinationPageSize), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.doRequest(req, authToken)
+		if err != nil {
+			return nil, err
+		}
+
+		response := destinationsResponse{}
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		destinations = append(destinations, response.Data...)
+		if len(response.Data) < destinationPageSize {
+			return destinations, nil
+		}
+	}
+}
+
+// AddDestinations - Adds destinations to a destination list
+func (c *apiClient) AddDestinations(listID int64, destinations []Destination, authToken *string) error {
+	for start := 0; start < len(destinations); start += destinationBatchSize {
+		end := start + destinationBatchSize
+		if end > len(destinations) {
+			end = len(destinations)
+		}
+
+		rb, err := json.Marshal(destinations[start:end])
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequest("POST", fmt.Sprintf("%s/policies/v2/destinationlists/%d/destinations", c.HostURL, listID), bytes.NewBuffer(rb))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+		_, err = c.doRequest(req, authToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveDestinations - Removes destinations from a destination list by destination ID
+func (c *apiClient) RemoveDestinations(listID int64, destinationIDs []int64, authToken *string) error {
+	for start := 0; start < len(destinationIDs); start += destinationBatchSize {
+		end := start + destinationBatchSize
+		if end > len(destinationIDs) {
+			end = len(destinationIDs)
+		}
+
+		rb, err := json.Marshal(destinationIDs[start:end])
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/policies/v2/destinationlists/%d/destinations/remove", c.HostURL, listID), bytes.NewBuffer(rb))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+		_, err = c.doRequest(req, authToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// deviceSettingBatchSize is the largest number of devices the API accepts in
+// a single device settings request.
+const deviceSettingBatchSize = 500
+
+// swgEnabledSetting is the device setting that overrides whether a roaming
+// computer sends web traffic to the Secure Web Gateway.
+const swgEnabledSetting = "SWGEnabled"
+
+// DeviceSetting is the override of a setting on one device.
+type DeviceSetting struct {
+	OriginId   int64  `json:"originId"`
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	ModifiedAt string `json:"modifiedAt,omitempty"`
+}
+
+// DeviceSettingResult is the outcome of changing the setting of one device.
+type DeviceSettingResult struct {
+	OriginId int64  `json:"originId"`
+	Code     int    `json:"code"`
+	Message  string `json:"message,omitempty"`
+}
+
+type deviceSettingsRequest struct {
+	OriginIds []int64 `json:"originIds"`
+	Value     string  `json:"value,omitempty"`
+}
+
+type deviceSettingsResponse struct {
+	TotalCount   int64                 `json:"totalCount"`
+	SuccessCount int64                 `json:"successCount"`
+	FailCount    int64                 `json:"failCount"`
+	Items        []DeviceSettingResult `json:"items"`
+}
+
+// ListDeviceSettings - Returns the overrides of setting on the devices with
+// the given origin IDs. Devices without an override are left out
+func (c *apiClient) ListDeviceSettings(setting string, originIDs []int64, authToken *string) ([]DeviceSetting, error) {
+	settings := []DeviceSetting{}
+
+	for start := 0; start < len(originIDs); start += deviceSettingBatchSize {
+		end := start + deviceSettingBatchSize
+		if end > len(originIDs) {
+			end = len(originIDs)
+		}
+
+		body, err := c.deviceSettingsRequest(setting, "list", deviceSettingsRequest{OriginIds: originIDs[start:end]}, authToken)
+		if err != nil {
+			return nil, err
+		}
+
+		batch := []DeviceSetting{}
+		err = json.Unmarshal(body, &batch)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, batch...)
+	}
+
+	return settings, nil
+}
+
+// SetDeviceSetting - Overrides setting with value on the devices with the
+// given origin IDs
+func (c *apiClient) SetDeviceSetting(setting string, value string, originIDs []int64, authToken *string) ([]DeviceSettingResult, error) {
+	return c.changeDeviceSetting(setting, "set", value, originIDs, authToken)
+}
+
+// RemoveDeviceSetting - Clears the override of setting on the devices with
+// the given origin IDs
+func (c *apiClient) RemoveDeviceSetting(setting string, originIDs []int64, authToken *string) ([]DeviceSettingResult, error) {
+	return c.changeDeviceSetting(setting, "remove", "", originIDs, authToken)
+}
+
+// changeDeviceSetting applies action in batches. When a batch fails, the
+// results of the batches before it are returned along with the error.
+func (c *apiClient) changeDeviceSetting(setting string, action string, value string, originIDs []int64, authToken *string) ([]DeviceSettingResult, error) {
+	results := []DeviceSettingResult{}
+
+	for start := 0; start < len(originIDs); start += deviceSettingBatchSize {
+		end := start + deviceSettingBatchSize
+		if end > len(originIDs) {
+			end = len(originIDs)
+		}
+
+		body, err := c.deviceSettingsRequest(setting, action, deviceSettingsRequest{OriginIds: originIDs[start:end], Value: value}, authToken)
+		if err != nil {
+			return results, err
+		}
+
+		response := deviceSettingsResponse{}
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, response.Items...)
+	}
+
+	return results, nil
+}
+
+func (c *apiClient) deviceSettingsRequest(setting string, action string, request deviceSettingsRequest, authToken *string) ([]byte, error) {
+	rb, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/deployments/v2/deviceSettings/%s/%s", c.HostURL, url.PathEscape(setting), action), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	return c.doRequest(req, authToken)
+}
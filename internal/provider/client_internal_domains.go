@@ -0,0 +1,105 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type InternalDomain struct {
+	Id                      int64   `json:"id,omitempty"`
+	Domain                  string  `json:"domain"`
+	Description             string  `json:"description,omitempty"`
+	IncludeAllVAs           bool    `json:"includeAllVAs"`
+	IncludeAllMobileDevices bool    `json:"includeAllMobileDevices"`
+	SiteIds                 []int64 `json:"siteIds"`
+	CreatedAt               string  `json:"createdAt,omitempty"`
+	ModifiedAt              string  `json:"modifiedAt,omitempty"`
+}
+
+// GetInternalDomain - Returns a specific internal domain
+func (c *apiClient) GetInternalDomain(domainID int64, authToken *string) (*InternalDomain, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/internaldomains/%d", c.HostURL, domainID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := InternalDomain{}
+	err = json.Unmarshal(body, &domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain, nil
+}
+
+// CreateInternalDomain - Create new internal domain
+func (c *apiClient) CreateInternalDomain(domainItem InternalDomain, authToken *string) (*InternalDomain, error) {
+	rb, err := json.Marshal(domainItem)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/deployments/v2/internaldomains", c.HostURL), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := InternalDomain{}
+	err = json.Unmarshal(body, &domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain, nil
+}
+
+// UpdateInternalDomain - Updates an internal domain
+func (c *apiClient) UpdateInternalDomain(domainID int64, domainItem InternalDomain, authToken *string) (*InternalDomain, error) {
+	rb, err := json.Marshal(domainItem)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/deployments/v2/internaldomains/%d", c.HostURL, domainID), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := InternalDomain{}
+	err = json.Unmarshal(body, &domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain, nil
+}
+
+// DeleteInternalDomain - Deletes an internal domain
+func (c *apiClient) DeleteInternalDomain(domainID int64, authToken *string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/deployments/v2/internaldomains/%d", c.HostURL, domainID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
@@ -0,0 +1,111 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// InternalNetwork is the internal network model of the deployments API. The
+// upstream umbrella.Internalnetwork lacks the origin ID and truncates site IDs.
+type InternalNetwork struct {
+	OriginId     int64  `json:"originId,omitempty"`
+	Name         string `json:"name"`
+	IpAddress    string `json:"ipAddress"`
+	PrefixLength int64  `json:"prefixLength"`
+	SiteId       int64  `json:"siteId,omitempty"`
+	SiteName     string `json:"siteName,omitempty"`
+	NetworkId    int64  `json:"networkId,omitempty"`
+	NetworkName  string `json:"networkName,omitempty"`
+	TunnelId     int64  `json:"tunnelId,omitempty"`
+	TunnelName   string `json:"tunnelName,omitempty"`
+	CreatedAt    string `json:"createdAt,omitempty"`
+	ModifiedAt   string `json:"modifiedAt,omitempty"`
+}
+
+// GetInternalNetwork - Returns a specific internal network
+func (c *apiClient) GetInternalNetwork(networkID int64, authToken *string) (*InternalNetwork, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/internalnetworks/%d", c.HostURL, networkID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	network := InternalNetwork{}
+	err = json.Unmarshal(body, &network)
+	if err != nil {
+		return nil, err
+	}
+
+	return &network, nil
+}
+
+// CreateInternalNetwork - Create new internal network
+func (c *apiClient) CreateInternalNetwork(networkItem InternalNetwork, authToken *string) (*InternalNetwork, error) {
+	rb, err := json.Marshal(networkItem)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/deployments/v2/internalnetworks", c.HostURL), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	network := InternalNetwork{}
+	err = json.Unmarshal(body, &network)
+	if err != nil {
+		return nil, err
+	}
+
+	return &network, nil
+}
+
+// UpdateInternalNetwork - Updates an internal network
+func (c *apiClient) UpdateInternalNetwork(networkID int64, networkItem InternalNetwork, authToken *string) (*InternalNetwork, error) {
+	rb, err := json.Marshal(networkItem)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/deployments/v2/internalnetworks/%d", c.HostURL, networkID), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	network := InternalNetwork{}
+	err = json.Unmarshal(body, &network)
+	if err != nil {
+		return nil, err
+	}
+
+	return &network, nil
+}
+
+// DeleteInternalNetwork - Deletes an internal network
+func (c *apiClient) DeleteInternalNetwork(networkID int64, authToken *string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/deployments/v2/internalnetworks/%d", c.HostURL, networkID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
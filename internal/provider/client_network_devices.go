@@ -0,0 +1,130 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// NetworkDevice is a router, such as an ISR or a Meraki MX, that forwards
+// DNS queries to Umbrella tagged with its origin ID.
+type NetworkDevice struct {
+	OriginId       int64  `json:"originId,omitempty"`
+	DeviceId       string `json:"deviceId,omitempty"`
+	OrganizationId int64  `json:"organizationId,omitempty"`
+	Model          string `json:"model,omitempty"`
+	MacAddress     string `json:"macAddress,omitempty"`
+	Name           string `json:"name"`
+	SerialNumber   string `json:"serialNumber,omitempty"`
+	Tag            string `json:"tag,omitempty"`
+	CreatedAt      string `json:"createdAt,omitempty"`
+}
+
+// GetNetworkDevices - Returns all network devices
+func (c *apiClient) GetNetworkDevices(authToken *string) ([]NetworkDevice, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/networkdevices", c.HostURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := []NetworkDevice{}
+	err = json.Unmarshal(body, &devices)
+	if err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// GetNetworkDevice - Returns a specific network device
+func (c *apiClient) GetNetworkDevice(originID int64, authToken *string) (*NetworkDevice, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/networkdevices/%d", c.HostURL, originID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	device := NetworkDevice{}
+	err = json.Unmarshal(body, &device)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
+
+// CreateNetworkDevice - Registers a new network device
+func (c *apiClient) CreateNetworkDevice(deviceItem NetworkDevice, authToken *string) (*NetworkDevice, error) {
+	rb, err := json.Marshal(deviceItem)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/deployments/v2/networkdevices", c.HostURL), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	device := NetworkDevice{}
+	err = json.Unmarshal(body, &device)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
+
+// RenameNetworkDevice - Changes the name of a network device, the only
+// attribute that can change after registration
+func (c *apiClient) RenameNetworkDevice(originID int64, name string, authToken *string) (*NetworkDevice, error) {
+	rb, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/deployments/v2/networkdevices/%d", c.HostURL, originID), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	device := NetworkDevice{}
+	err = json.Unmarshal(body, &device)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
+
+// DeleteNetworkDevice - Deregisters a network device
+func (c *apiClient) DeleteNetworkDevice(originID int64, authToken *string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/deployments/v2/networkdevices/%d", c.HostURL, originID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
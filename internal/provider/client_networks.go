@@ -0,0 +1,129 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Network is the network model of the deployments API. The upstream
+// umbrella.Network truncates prefix lengths, and the upstream client only
+// lists and creates networks, printing every request it sends.
+type Network struct {
+	OriginId     int64  `json:"originId,omitempty"`
+	Name         string `json:"name"`
+	IpAddress    string `json:"ipAddress,omitempty"`
+	PrefixLength int64  `json:"prefixLength,omitempty"`
+	IsDynamic    bool   `json:"isDynamic"`
+	IsVerified   bool   `json:"isVerified,omitempty"`
+	Status       string `json:"status,omitempty"`
+	CreatedAt    string `json:"createdAt,omitempty"`
+}
+
+// GetNetworks - Returns all networks
+func (c *apiClient) GetNetworks(authToken *string) ([]Network, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/networks", c.HostURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := []Network{}
+	err = json.Unmarshal(body, &networks)
+	if err != nil {
+		return nil, err
+	}
+
+	return networks, nil
+}
+
+// GetNetwork - Returns a specific network
+func (c *apiClient) GetNetwork(networkID int64, authToken *string) (*Network, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/networks/%d", c.HostURL, networkID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	network := Network{}
+	err = json.Unmarshal(body, &network)
+	if err != nil {
+		return nil, err
+	}
+
+	return &network, nil
+}
+
+// CreateNetwork - Registers a new network
+func (c *apiClient) CreateNetwork(networkItem Network, authToken *string) (*Network, error) {
+	rb, err := json.Marshal(networkItem)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/deployments/v2/networks", c.HostURL), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	network := Network{}
+	err = json.Unmarshal(body, &network)
+	if err != nil {
+		return nil, err
+	}
+
+	return &network, nil
+}
+
+// UpdateNetwork - Updates a network
+func (c *apiClient) UpdateNetwork(networkID int64, networkItem Network, authToken *string) (*Network, error) {
+	rb, err := json.Marshal(networkItem)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/deployments/v2/networks/%d", c.HostURL, networkID), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	network := Network{}
+	err = json.Unmarshal(body, &network)
+	if err != nil {
+		return nil, err
+	}
+
+	return &network, nil
+}
+
+// DeleteNetwork - Unregisters a network
+func (c *apiClient) DeleteNetwork(networkID int64, authToken *string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/deployments/v2/networks/%d", c.HostURL, networkID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
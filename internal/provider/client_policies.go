@@ -0,0 +1,75 @@
+package umbrellaprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// policyPageSize is the page size used when listing policies.
+const policyPageSize = 100
+
+// policyTypes are the kinds of policy the deployments API lists.
+var policyTypes = []string{"dns", "web"}
+
+// Policy is a DNS or web policy. Identities are attached to the policy by
+// their origin ID.
+type Policy struct {
+	Id             int64  `json:"id"`
+	OrganizationId int64  `json:"organizationId,omitempty"`
+	Name           string `json:"name"`
+	IsDefault      bool   `json:"isDefault"`
+	Priority       int64  `json:"priority,omitempty"`
+	CreatedAt      string `json:"createdAt,omitempty"`
+}
+
+// GetPolicies - Returns every policy of policyType, "dns" or "web"
+func (c *apiClient) GetPolicies(policyType string, authToken *string) ([]Policy, error) {
+	policies := []Policy{}
+
+	for page := 1; ; page++ {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/policies?type=%s&page=%d&limit=%d", c.HostURL, url.QueryEscape(policyType), page, policyPageSize), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.doRequest(req, authToken)
+		if err != nil {
+			return nil, err
+		}
+
+		pagePolicies := []Policy{}
+		err = json.Unmarshal(body, &pagePolicies)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, pagePolicies...)
+		if len(pagePolicies) < policyPageSize {
+			return policies, nil
+		}
+	}
+}
+
+// AddPolicyIdentity - Attaches the identity with originID to a policy
+func (c *apiClient) AddPolicyIdentity(policyID int64, originID int64, authToken *string) error {
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/deployments/v2/policies/%d/identities/%d", c.HostURL, policyID, originID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
+
+// RemovePolicyIdentity - Detaches the identity with originID from a policy
+func (c *apiClient) RemovePolicyIdentity(policyID int64, originID int64, authToken *string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/deployments/v2/policies/%d/identities/%d", c.HostURL, policyID, originID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
@@ -0,0 +1,118 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// roamingComputerPageSize is the page size used when listing roaming computers.
+const roamingComputerPageSize = 100
+
+// RoamingComputer is a computer running the roaming module of the Secure
+// Client, as returned by the deployments API.
+type RoamingComputer struct {
+	OriginId          int64  `json:"originId,omitempty"`
+	DeviceId          string `json:"deviceId,omitempty"`
+	Type              string `json:"type,omitempty"`
+	Name              string `json:"name,omitempty"`
+	Status            string `json:"status,omitempty"`
+	SwgStatus         string `json:"swgStatus,omitempty"`
+	LastSyncStatus    string `json:"lastSyncStatus,omitempty"`
+	LastSyncSwgStatus string `json:"lastSyncSwgStatus,omitempty"`
+	LastSync          string `json:"lastSync,omitempty"`
+	Version           string `json:"version,omitempty"`
+	OsVersion         string `json:"osVersion,omitempty"`
+	OsVersionName     string `json:"osVersionName,omitempty"`
+	HasIpBlocking     bool   `json:"hasIpBlocking,omitempty"`
+	Tags              []Tag  `json:"tags,omitempty"`
+}
+
+// GetRoamingComputers - Returns every roaming computer
+func (c *apiClient) GetRoamingComputers(authToken *string) ([]RoamingComputer, error) {
+	computers := []RoamingComputer{}
+
+	for page := 1; ; page++ {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/roamingcomputers?page=%d&limit=%d", c.HostURL, page, roamingComputerPageSize), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.doRequest(req, authToken)
+		if err != nil {
+			return nil, err
+		}
+
+		pageComputers := []RoamingComputer{}
+		err = json.Unmarshal(body, &pageComputers)
+		if err != nil {
+			return nil, err
+		}
+
+		computers = append(computers, pageComputers...)
+		if len(pageComputers) < roamingComputerPageSize {
+			return computers, nil
+		}
+	}
+}
+
+// GetRoamingComputer - Returns a specific roaming computer
+func (c *apiClient) GetRoamingComputer(deviceID string, authToken *string) (*RoamingComputer, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/roamingcomputers/%s", c.HostURL, url.PathEscape(deviceID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	computer := RoamingComputer{}
+	err = json.Unmarshal(body, &computer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &computer, nil
+}
+
+// RenameRoamingComputer - Changes the name of a roaming computer
+func (c *apiClient) RenameRoamingComputer(deviceID string, name string, authToken *string) (*RoamingComputer, error) {
+	rb, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/deployments/v2/roamingcomputers/%s", c.HostURL, url.PathEscape(deviceID)), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	computer := RoamingComputer{}
+	err = json.Unmarshal(body, &computer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &computer, nil
+}
+
+// DeleteRoamingComputer - Deletes a roaming computer
+func (c *apiClient) DeleteRoamingComputer(deviceID string, authToken *string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/deployments/v2/roamingcomputers/%s", c.HostURL, url.PathEscape(deviceID)), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
@@ -0,0 +1,135 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// tagDeviceBatchSize is the largest number of devices the API accepts in a
+// single add or remove request.
+const tagDeviceBatchSize = 500
+
+// Tag is a label that groups roaming computers and other devices.
+type Tag struct {
+	Id         int64  `json:"id,omitempty"`
+	Name       string `json:"name"`
+	CreatedAt  string `json:"createdAt,omitempty"`
+	ModifiedAt string `json:"modifiedAt,omitempty"`
+}
+
+// tagDevicesRequest adds devices to, or removes them from, a tag.
+type tagDevicesRequest struct {
+	AddOrigins    []int64 `json:"addOrigins,omitempty"`
+	RemoveOrigins []int64 `json:"removeOrigins,omitempty"`
+}
+
+// GetTags - Returns all tags
+func (c *apiClient) GetTags(authToken *string) ([]Tag, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/tags", c.HostURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []Tag{}
+	err = json.Unmarshal(body, &tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// CreateTag - Creates a new tag
+func (c *apiClient) CreateTag(name string, authToken *string) (*Tag, error) {
+	rb, err := json.Marshal(Tag{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/deployments/v2/tags", c.HostURL), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := Tag{}
+	err = json.Unmarshal(body, &tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// DeleteTag - Deletes a tag
+func (c *apiClient) DeleteTag(tagID int64, authToken *string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/deployments/v2/tags/%d", c.HostURL, tagID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
+
+// AddTagDevices - Tags the devices with the given origin IDs
+func (c *apiClient) AddTagDevices(tagID int64, originIDs []int64, authToken *string) error {
+	for start := 0; start < len(originIDs); start += tagDeviceBatchSize {
+		end := start + tagDeviceBatchSize
+		if end > len(originIDs) {
+			end = len(originIDs)
+		}
+
+		err := c.changeTagDevices("POST", tagID, tagDevicesRequest{AddOrigins: originIDs[start:end]}, authToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveTagDevices - Untags the devices with the given origin IDs
+func (c *apiClient) RemoveTagDevices(tagID int64, originIDs []int64, authToken *string) error {
+	for start := 0; start < len(originIDs); start += tagDeviceBatchSize {
+		end := start + tagDeviceBatchSize
+		if end > len(originIDs) {
+			end = len(originIDs)
+		}
+
+		err := c.changeTagDevices("DELETE", tagID, tagDevicesRequest{RemoveOrigins: originIDs[start:end]}, authToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *apiClient) changeTagDevices(method string, tagID int64, change tagDevicesRequest, authToken *string) error {
+	rb, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/deployments/v2/tags/%d/devices", c.HostURL, tagID), bytes.NewBuffer(rb))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
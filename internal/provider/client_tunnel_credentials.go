@@ -0,0 +1,76 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// TunnelCredential is a pre-shared key registered for a tunnel. A tunnel can
+// hold more than one credential while a new key is being rolled out; the API
+// never returns the secret of an existing credential.
+type TunnelCredential struct {
+	Id        string `json:"id,omitempty"`
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// GetTunnelCredentials - Returns the credentials of a tunnel
+func (c *apiClient) GetTunnelCredentials(tunnelID int64, authToken *string) ([]TunnelCredential, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/tunnels/%d/keys", c.HostURL, tunnelID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := []TunnelCredential{}
+	err = json.Unmarshal(body, &credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// CreateTunnelCredential - Adds a credential with secret to a tunnel
+func (c *apiClient) CreateTunnelCredential(tunnelID int64, secret string, authToken *string) (*TunnelCredential, error) {
+	rb, err := json.Marshal(TunnelCredential{Secret: secret})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/deployments/v2/tunnels/%d/keys", c.HostURL, tunnelID), bytes.NewBuffer(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := TunnelCredential{}
+	err = json.Unmarshal(body, &credential)
+	if err != nil {
+		return nil, err
+	}
+
+	return &credential, nil
+}
+
+// DeleteTunnelCredential - Deletes a credential of a tunnel
+func (c *apiClient) DeleteTunnelCredential(tunnelID int64, credentialID string, authToken *string) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/deployments/v2/tunnels/%d/keys/%s", c.HostURL, tunnelID, credentialID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doRequest(req, authToken)
+	return err
+}
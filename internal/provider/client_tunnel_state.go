@@ -0,0 +1,45 @@
+package umbrellaprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// TunnelState is the runtime state of a tunnel, as seen by each Umbrella data
+// center the tunnel peers with.
+type TunnelState struct {
+	TunnelId    int64                   `json:"tunnelId"`
+	Status      string                  `json:"status"`
+	ModifiedAt  string                  `json:"modifiedAt,omitempty"`
+	DataCenters []TunnelDataCenterState `json:"dataCenters"`
+}
+
+type TunnelDataCenterState struct {
+	Dc                string `json:"dc"`
+	PeerIp            string `json:"peerIp"`
+	IkeStatus         string `json:"ikeStatus"`
+	IpsecStatus       string `json:"ipsecStatus"`
+	LastEstablishedAt string `json:"lastEstablishedAt,omitempty"`
+}
+
+// GetTunnelState - Returns the state of a tunnel in every data center
+func (c *apiClient) GetTunnelState(tunnelID int64, authToken *string) (*TunnelState, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/deployments/v2/tunnels/%d/state", c.HostURL, tunnelID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req, authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	state := TunnelState{}
+	err = json.Unmarshal(body, &state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/olegunza/umbrella-api-go/umbrella"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -21,11 +20,12 @@ func NewDClistDataSource() datasource.DataSource {
 }
 
 type DCListDataSource struct {
-	client *umbrella.Client
+	client *apiClient
 }
 
 type DCListDataSourceModel struct {
-	Continents types.List `tfsdk:"continents"`
+	ID         types.String `tfsdk:"id"`
+	Continents types.List   `tfsdk:"continents"`
 }
 type City struct {
 	Latitude  types.String `tfsdk:"latitude"`
@@ -50,6 +50,10 @@ func (d *DCListDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "DC list data source",
 		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Placeholder identifier of the data source",
+				Computed:            true,
+			},
 			"continents": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -103,12 +107,12 @@ func (d *DCListDataSource) Configure(ctx context.Context, req datasource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(*umbrella.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -135,13 +139,10 @@ func (d *DCListDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	dclist, err := d.client.GetDCs(&d.client.Token)
+	dclist, err := d.client.GetDCs(nil)
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Umbrella Dc List",
-			err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, "Unable to Read Umbrella Dc List", "Could not read the Umbrella data center list", err, nil)
 		return
 	}
 
@@ -172,6 +173,7 @@ func (d *DCListDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 	continents, _ := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: typeFromAttrs(continentsResourceAttr())}, dccontlist)
 	data.Continents = continents
+	data.ID = types.StringValue("dclist")
 
 	tflog.Trace(ctx, "read a data source")
 
@@ -0,0 +1,28 @@
+package umbrellaprovider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDCListDataSource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+data "umbrella_dclist" "test" {
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.umbrella_dclist.test", "continents.#", "2"),
+					resource.TestCheckResourceAttr("data.umbrella_dclist.test", "continents.0.cities.0.dc", "ams1"),
+				),
+			},
+		},
+	})
+}
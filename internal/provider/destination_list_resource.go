@@ -0,0 +1,541 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &DestinationListResource{}
+var _ resource.ResourceWithImportState = &DestinationListResource{}
+
+// bundleTypes maps the bundle_type attribute to the API bundleTypeId.
+var bundleTypes = map[string]int64{
+	"dns": 1,
+	"web": 2,
+}
+
+var (
+	destinationListAccesses    = []string{"allow", "block"}
+	destinationListBundleTypes = []string{"dns", "web"}
+)
+
+func NewDestinationListResource() resource.Resource {
+	return &DestinationListResource{}
+}
+
+// DestinationListResource defines the resource implementation.
+type DestinationListResource struct {
+	client *apiClient
+}
+
+// DestinationListResourceModel describes the resource data model.
+type DestinationListResourceModel struct {
+	Id           types.Int64  `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Access       types.String `tfsdk:"access"`
+	IsGlobal     types.Bool   `tfsdk:"is_global"`
+	BundleType   types.String `tfsdk:"bundle_type"`
+	Destinations types.Set    `tfsdk:"destinations"`
+	ModifiedAt   types.String `tfsdk:"modified_at"`
+	CreatedAt    types.String `tfsdk:"created_at"`
+	LastUpdated  types.String `tfsdk:"last_updated"`
+	OrgId        types.Int64  `tfsdk:"org_id"`
+}
+
+type DestinationResourceModel struct {
+	Destination types.String `tfsdk:"destination"`
+	Comment     types.String `tfsdk:"comment"`
+}
+
+func (o DestinationResourceModel) attrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"destination": types.StringType,
+		"comment":     types.StringType,
+	}
+}
+
+// key identifies a destination entry; changing only the comment of a
+// destination replaces the entry.
+func (o DestinationResourceModel) key() string {
+	return o.Destination.ValueString() + "\x00" + o.Comment.ValueString()
+}
+
+func (r *DestinationListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_destination_list"
+}
+
+func (r *DestinationListResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Destination list resource. Destinations are added and removed in place, so changing them does not recreate the list",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the destination list",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the destination list",
+				Required:            true,
+			},
+			"access": schema.StringAttribute{
+				MarkdownDescription: "The type of access for the destination list, one of " + quotedList(destinationListAccesses),
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringOneOf(destinationListAccesses...),
+				},
+			},
+			"is_global": schema.BoolAttribute{
+				MarkdownDescription: "Specifies whether the destination list is a global destination list",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"bundle_type": schema.StringAttribute{
+				MarkdownDescription: "The type of policy the destination list applies to, one of " + quotedList(destinationListBundleTypes) + ". Defaults to `dns`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringOneOf(destinationListBundleTypes...),
+				},
+			},
+			"destinations": schema.SetNestedAttribute{
+				MarkdownDescription: "The domains, URLs and IPv4 addresses in the destination list",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"destination": schema.StringAttribute{
+							MarkdownDescription: "A domain, URL or IPv4 address",
+							Required:            true,
+						},
+						"comment": schema.StringAttribute{
+							MarkdownDescription: "A comment about the destination",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"modified_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the destination list was modified",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the destination list was created",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the destination list. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+	}
+}
+
+func (r *DestinationListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *DestinationListResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *DestinationListResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId)
+
+	listItem := DestinationList{
+		Name:     data.Name.ValueString(),
+		Access:   data.Access.ValueString(),
+		IsGlobal: data.IsGlobal.ValueBool(),
+	}
+	if !data.BundleType.IsNull() && !data.BundleType.IsUnknown() {
+		bundleTypeId, ok := bundleTypes[data.BundleType.ValueString()]
+		if !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("bundle_type"),
+				"Invalid Destination List Bundle Type",
+				fmt.Sprintf("Expected bundle_type to be one of dns or web, got: %s", data.BundleType.ValueString()),
+			)
+			return
+		}
+		listItem.BundleTypeId = bundleTypeId
+	}
+
+	list, err := api.CreateDestinationList(listItem, nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Creating Umbrella Destination List", "Could not create Umbrella Destination List", err, apiErrorPaths{http.StatusConflict: path.Root("name")})
+		return
+	}
+
+	// Save the list before adding destinations so that a failed batch does
+	// not orphan it.
+	data.Id = types.Int64Value(list.Id)
+	data.OrgId = api.orgIDValue()
+	destinations := data.Destinations
+	data.Destinations = types.SetNull(types.ObjectType{AttrTypes: DestinationResourceModel{}.attrTypes()})
+	setDestinationListModel(data, list)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Destinations = destinations
+	resp.Diagnostics.Append(r.syncDestinations(ctx, api, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags := r.read(ctx, api, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *DestinationListResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *DestinationListResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId)
+
+	found, diags := r.read(ctx, api, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		tflog.Warn(ctx, "Umbrella Destination List no longer exists, removing it from state", map[string]interface{}{
+			"id": data.Id.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *DestinationListResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *DestinationListResourceModel
+	var statedata *DestinationListResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &statedata)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(statedata.OrgId)
+	data.Id = statedata.Id
+
+	if !data.Name.Equal(statedata.Name) {
+		_, err := api.RenameDestinationList(statedata.Id.ValueInt64(), data.Name.ValueString(), nil)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Error Updating Umbrella Destination List", "Could not rename Umbrella Destination List ID "+strconv.FormatInt(statedata.Id.ValueInt64(), 10), err, apiErrorPaths{http.StatusConflict: path.Root("name")})
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(r.syncDestinations(ctx, api, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags := r.read(ctx, api, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *DestinationListResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *DestinationListResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.forOrg(data.OrgId).DeleteDestinationList(data.Id.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Destination List is already gone", map[string]interface{}{
+			"id": data.Id.ValueInt64(),
+		})
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Destination List", "Could not delete Umbrella Destination List ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, nil)
+		return
+	}
+}
+
+func (r *DestinationListResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	orgid, listid, err := parseImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Umbrella Destination List Import ID",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), listid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
+}
+
+// read refreshes data from the destination list and its destinations. It
+// returns false when the list no longer exists.
+func (r *DestinationListResource) read(ctx context.Context, api *apiClient, data *DestinationListResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	list, err := api.GetDestinationList(data.Id.ValueInt64(), nil)
+	if isNotFound(err) {
+		return false, diags
+	}
+	if err != nil {
+		addAPIError(&diags, "Error Reading Umbrella Destination List", "Could not read Umbrella Destination List ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, nil)
+		return true, diags
+	}
+
+	destinations, err := api.GetDestinations(data.Id.ValueInt64(), nil)
+	if err != nil {
+		addAPIError(&diags, "Error Reading Umbrella Destination List", "Could not read destinations of Umbrella Destination List ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, nil)
+		return true, diags
+	}
+
+	setDestinationListModel(data, list)
+	data.OrgId = api.orgIDValue()
+
+	// An unset destinations attribute stays unset while the list is empty.
+	elemType := types.ObjectType{AttrTypes: DestinationResourceModel{}.attrTypes()}
+	if len(destinations) == 0 && data.Destinations.IsNull() {
+		data.Destinations = types.SetNull(elemType)
+		return true, diags
+	}
+
+	items := make([]DestinationResourceModel, 0, len(destinations))
+	for _, destination := range destinations {
+		item := DestinationResourceModel{
+			Destination: types.StringValue(destination.Destination),
+			Comment:     types.StringNull(),
+		}
+		if destination.Comment != "" {
+			item.Comment = types.StringValue(destination.Comment)
+		}
+		items = append(items, item)
+	}
+
+	set, d := types.SetValueFrom(ctx, elemType, items)
+	diags.Append(d...)
+	data.Destinations = set
+
+	return true, diags
+}
+
+// syncDestinations adds and removes destinations so that the list matches
+// the planned destinations, without recreating the list.
+func (r *DestinationListResource) syncDestinations(ctx context.Context, api *apiClient, data *DestinationListResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	listid := data.Id.ValueInt64()
+
+	var planned []DestinationResourceModel
+	if !data.Destinations.IsNull() && !data.Destinations.IsUnknown() {
+		diags.Append(data.Destinations.ElementsAs(ctx, &planned, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	current, err := api.GetDestinations(listid, nil)
+	if err != nil {
+		addAPIError(&diags, "Error Reading Umbrella Destination List", "Could not read destinations of Umbrella Destination List ID "+strconv.FormatInt(listid, 10), err, nil)
+		return diags
+	}
+
+	wanted := map[string]bool{}
+	wantedDestinations := map[string]bool{}
+	for _, destination := range planned {
+		wanted[destination.key()] = true
+		wantedDestinations[destination.Destination.ValueString()] = true
+	}
+
+	// Entries of destinations that stay in the list with another comment are
+	// replaced, and removed before the new entries are added in case the API
+	// keeps the existing entry of a destination rather than adding another.
+	existing := map[string]bool{}
+	var replace, remove []int64
+	for _, destination := range current {
+		key := DestinationResourceModel{
+			Destination: types.StringValue(destination.Destination),
+			Comment:     types.StringValue(destination.Comment),
+		}.key()
+		if wanted[key] && !existing[key] {
+			existing[key] = true
+			continue
+		}
+		id, err := strconv.ParseInt(destination.Id, 10, 64)
+		if err != nil {
+			diags.AddError(
+				"Error Updating Umbrella Destination List",
+				fmt.Sprintf("Unexpected ID %q for destination %s of Umbrella Destination List ID %d", destination.Id, destination.Destination, listid),
+			)
+			return diags
+		}
+		if wantedDestinations[destination.Destination] {
+			replace = append(replace, id)
+		} else {
+			remove = append(remove, id)
+		}
+	}
+
+	var add []Destination
+	for _, destination := range planned {
+		if existing[destination.key()] {
+			continue
+		}
+		add = append(add, Destination{
+			Destination: destination.Destination.ValueString(),
+			Comment:     destination.Comment.ValueString(),
+		})
+	}
+
+	tflog.Debug(ctx, "syncing destination list", map[string]interface{}{
+		"id":      listid,
+		"add":     len(add),
+		"replace": len(replace),
+		"remove":  len(remove),
+	})
+
+	if len(replace) > 0 {
+		err = api.RemoveDestinations(listid, replace, nil)
+		if err != nil {
+			addAPIError(&diags, "Error Updating Umbrella Destination List", "Could not remove destinations from Umbrella Destination List ID "+strconv.FormatInt(listid, 10), err, nil)
+			return diags
+		}
+	}
+
+	// Add before removing the destinations that leave the list, so that
+	// swapping one destination for another never leaves a gap between them.
+	if len(add) > 0 {
+		err = api.AddDestinations(listid, add, nil)
+		if err != nil {
+			addAPIError(&diags, "Error Updating Umbrella Destination List", "Could not add destinations to Umbrella Destination List ID "+strconv.FormatInt(listid, 10), err, nil)
+			return diags
+		}
+	}
+
+	if len(remove) > 0 {
+		err = api.RemoveDestinations(listid, remove, nil)
+		if err != nil {
+			addAPIError(&diags, "Error Updating Umbrella Destination List", "Could not remove destinations from Umbrella Destination List ID "+strconv.FormatInt(listid, 10), err, nil)
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func setDestinationListModel(data *DestinationListResourceModel, list *DestinationList) {
+	data.Id = types.Int64Value(list.Id)
+	data.Name = types.StringValue(list.Name)
+	data.Access = types.StringValue(list.Access)
+	data.IsGlobal = types.BoolValue(list.IsGlobal)
+	data.BundleType = types.StringNull()
+	for name, id := range bundleTypes {
+		if id == list.BundleTypeId {
+			data.BundleType = types.StringValue(name)
+		}
+	}
+	data.CreatedAt = types.StringValue(time.Unix(list.CreatedAt, 0).UTC().Format(time.RFC3339))
+	data.ModifiedAt = types.StringValue(time.Unix(list.ModifiedAt, 0).UTC().Format(time.RFC3339))
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+}
@@ -0,0 +1,213 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDestinationListResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var listID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_destination_list" "test" {
+  name   = "blocked"
+  access = "block"
+
+  destinations = [
+    { destination = "bad.example.com", comment = "phishing" },
+    { destination = "worse.example.com" },
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_destination_list.test", "name", "blocked"),
+					resource.TestCheckResourceAttr("umbrella_destination_list.test", "bundle_type", "dns"),
+					resource.TestCheckResourceAttr("umbrella_destination_list.test", "is_global", "false"),
+					resource.TestCheckResourceAttr("umbrella_destination_list.test", "destinations.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("umbrella_destination_list.test", "destinations.*", map[string]string{
+						"destination": "bad.example.com",
+						"comment":     "phishing",
+					}),
+					testAccCaptureAttr("umbrella_destination_list.test", "id", &listID),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "umbrella_destination_list.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_destination_list" "test" {
+  name   = "still-blocked"
+  access = "block"
+
+  destinations = [
+    { destination = "bad.example.com", comment = "malware" },
+    { destination = "10.10.10.10" },
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_destination_list.test", "name", "still-blocked"),
+					resource.TestCheckResourceAttr("umbrella_destination_list.test", "destinations.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("umbrella_destination_list.test", "destinations.*", map[string]string{
+						"destination": "bad.example.com",
+						"comment":     "malware",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("umbrella_destination_list.test", "destinations.*", map[string]string{
+						"destination": "10.10.10.10",
+					}),
+					testAccCheckAttrUnchanged("umbrella_destination_list.test", "id", &listID),
+					func(s *terraform.State) error {
+						prefix := "/policies/v2/destinationlists/" + listID + "/destinations"
+						added := fake.requested("POST", prefix)
+						removed := fake.requested("DELETE", prefix+"/remove")
+						if len(added) < 2 || len(removed) != 2 || removed[1] < added[len(added)-1] {
+							return fmt.Errorf("expected destinations to be added before the old ones are removed, got adds at %v and removes at %v", added, removed)
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			if n := fake.destinationListCount(); n != 0 {
+				return fmt.Errorf("%d destination lists left behind", n)
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccDestinationListResource_commentOnly(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.dedupDestinations = true
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDestinationListResourceConfig(fake, "phishing"),
+			},
+			{
+				Config: testAccDestinationListResourceConfig(fake, "malware"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_destination_list.test", "destinations.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("umbrella_destination_list.test", "destinations.*", map[string]string{
+						"destination": "bad.example.com",
+						"comment":     "malware",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccDestinationListResourceConfig(fake *fakeUmbrella, comment string) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_destination_list" "test" {
+  name   = "blocked"
+  access = "block"
+
+  destinations = [
+    { destination = "bad.example.com", comment = %q },
+  ]
+}
+`, comment)
+}
+
+func TestAccDestinationListResource_invalidConfig(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_destination_list" "test" {
+  name   = "blocked"
+  access = "deny"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`access must be one of "allow", "block"`),
+			},
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_destination_list" "test" {
+  name        = "blocked"
+  access      = "block"
+  bundle_type = "ip"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`bundle_type must be one of "dns", "web"`),
+			},
+		},
+	})
+}
+
+func TestDestinationsAreBatched(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	client, err := newAPIClient(context.Background(), fake.server.URL, "fake-key", "fake-secret", 0, defaultRetryPolicy())
+	if err != nil {
+		t.Fatalf("newAPIClient: %s", err)
+	}
+
+	list, err := client.CreateDestinationList(DestinationList{Name: "big", Access: "allow"}, nil)
+	if err != nil {
+		t.Fatalf("CreateDestinationList: %s", err)
+	}
+
+	add := make([]Destination, 2*destinationBatchSize+1)
+	for i := range add {
+		add[i].Destination = "host" + strconv.Itoa(i) + ".example.com"
+	}
+	if err := client.AddDestinations(list.Id, add, nil); err != nil {
+		t.Fatalf("AddDestinations: %s", err)
+	}
+	if fake.destBatches != 3 {
+		t.Errorf("expected 3 add batches, got %d", fake.destBatches)
+	}
+
+	destinations, err := client.GetDestinations(list.Id, nil)
+	if err != nil {
+		t.Fatalf("GetDestinations: %s", err)
+	}
+	if len(destinations) != len(add) {
+		t.Fatalf("expected %d destinations, got %d", len(add), len(destinations))
+	}
+
+	remove := make([]int64, 0, len(destinations))
+	for _, destination := range destinations {
+		id, _ := strconv.ParseInt(destination.Id, 10, 64)
+		remove = append(remove, id)
+	}
+	if err := client.RemoveDestinations(list.Id, remove, nil); err != nil {
+		t.Fatalf("RemoveDestinations: %s", err)
+	}
+	if fake.destBatches != 6 {
+		t.Errorf("expected 3 remove batches, got %d", fake.destBatches-3)
+	}
+}
@@ -0,0 +1,191 @@
+package umbrellaprovider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// errNotFound matches, through errors.Is, API errors caused by the requested
+// object not existing.
+var errNotFound = errors.New("not found")
+
+// apiError is returned by the API layer when Umbrella answers with a non-2xx
+// status. Its message keeps the format of the upstream client; Code, Message
+// and RequestID are decoded from the error body when it is JSON.
+type apiError struct {
+	StatusCode int
+	Body       string
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+// apiErrorBody is the error body of the Umbrella APIs. The APIs disagree on
+// some field names, so the known spellings are all decoded.
+type apiErrorBody struct {
+	Error     string          `json:"error"`
+	Code      json.RawMessage `json:"code"`
+	ErrorCode json.RawMessage `json:"errorCode"`
+	Message   string          `json:"message"`
+	TxId      string          `json:"txId"`
+	RequestId string          `json:"requestId"`
+}
+
+// newAPIError decodes body, and the request ID header when header is not nil.
+func newAPIError(status int, body string, header http.Header) *apiError {
+	e := &apiError{StatusCode: status, Body: body}
+
+	var decoded apiErrorBody
+	if json.Unmarshal([]byte(body), &decoded) == nil {
+		e.Message = decoded.Message
+		e.Code = rawString(decoded.ErrorCode)
+		if e.Code == "" {
+			e.Code = rawString(decoded.Code)
+		}
+		if e.Code == "" {
+			e.Code = decoded.Error
+		}
+		e.RequestID = decoded.RequestId
+		if e.RequestID == "" {
+			e.RequestID = decoded.TxId
+		}
+	}
+	if e.RequestID == "" && header != nil {
+		e.RequestID = header.Get("X-Request-Id")
+	}
+
+	return e
+}
+
+// rawString returns a JSON string or number as a plain string.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+func (e *apiError) Is(target error) bool {
+	return target == errNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// wrapAPIError turns the plain errors returned by the upstream client for
+// non-2xx responses into *apiError. Other errors are returned unchanged.
+func wrapAPIError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return err
+	}
+
+	msg := err.Error()
+	rest := strings.TrimPrefix(msg, "status: ")
+	code, body, found := strings.Cut(rest, ", body: ")
+	if rest == msg || !found {
+		return err
+	}
+	status, convErr := strconv.Atoi(code)
+	if convErr != nil {
+		return err
+	}
+
+	return newAPIError(status, body, nil)
+}
+
+// isNotFound reports whether err means the requested object does not exist.
+func isNotFound(err error) bool {
+	return errors.Is(err, errNotFound)
+}
+
+// apiErrorPaths points API errors with the given status codes at the
+// attribute that most likely caused them.
+type apiErrorPaths map[int]path.Path
+
+// addAPIError adds a diagnostic for err, which happened while doing what detail
+// describes. Errors from the Umbrella API are explained from their status,
+// code, message and request ID, and are attached to the attribute paths maps
+// their status to.
+func addAPIError(diags *diag.Diagnostics, summary, detail string, err error, paths apiErrorPaths) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		diags.AddError(summary, detail+": "+err.Error())
+		return
+	}
+
+	// Keep the context that callers wrapped around the API error.
+	if wrapped := err.Error(); wrapped != apiErr.Error() && strings.HasSuffix(wrapped, ": "+apiErr.Error()) {
+		detail += ": " + strings.TrimSuffix(wrapped, ": "+apiErr.Error())
+	}
+	detail += ": " + describeAPIError(apiErr)
+	if attribute, ok := paths[apiErr.StatusCode]; ok {
+		diags.AddAttributeError(attribute, summary, detail)
+		return
+	}
+	diags.AddError(summary, detail)
+}
+
+// describeAPIError explains e for a diagnostic detail.
+func describeAPIError(e *apiError) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Umbrella API returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+
+	message := e.Message
+	if message == "" {
+		message = strings.TrimSpace(e.Body)
+		if len(message) > 500 {
+			message = message[:500] + "..."
+		}
+	}
+	if message != "" {
+		b.WriteString(": " + message)
+	}
+	if e.Code != "" && e.Code != http.StatusText(e.StatusCode) {
+		b.WriteString(" (error code " + e.Code + ")")
+	}
+	b.WriteString(".")
+
+	if hint := apiErrorHint(e.StatusCode); hint != "" {
+		b.WriteString(" " + hint)
+	}
+	if e.RequestID != "" {
+		b.WriteString("\n\nRequest ID: " + e.RequestID + ". Include it when contacting Umbrella support.")
+	}
+	return b.String()
+}
+
+func apiErrorHint(status int) string {
+	switch {
+	case status == http.StatusUnauthorized:
+		return "Check apikey and apisecret."
+	case status == http.StatusForbidden:
+		return "Check that the API key has the scopes this operation needs, and access to the organization."
+	case status == http.StatusNotFound:
+		return "The object does not exist, or belongs to another organization."
+	case status == http.StatusConflict:
+		return "The change conflicts with an existing object, most often one with the same name."
+	case status == http.StatusTooManyRequests:
+		return "Umbrella kept throttling the request after retrying. Raise max_retries or retry_max_wait, or try again later."
+	case status >= 500:
+		return "Umbrella could not handle the request. Trying again later usually helps."
+	}
+	return ""
+}
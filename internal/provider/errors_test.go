@@ -0,0 +1,115 @@
+package umbrellaprovider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestWrapAPIError(t *testing.T) {
+	err := wrapAPIError(fmt.Errorf("status: %d, body: %s", 404, `{"message":"Not Found"}`))
+
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *apiError, got %T", err)
+	}
+	if apiErr.StatusCode != 404 || apiErr.Body != `{"message":"Not Found"}` {
+		t.Errorf("unexpected apiError %+v", apiErr)
+	}
+	if err.Error() != `status: 404, body: {"message":"Not Found"}` {
+		t.Errorf("expected the upstream message to be kept, got %q", err.Error())
+	}
+	if !isNotFound(err) {
+		t.Error("expected a 404 to be not found")
+	}
+
+	if isNotFound(wrapAPIError(fmt.Errorf("status: 500, body: oops"))) {
+		t.Error("expected a 500 not to be not found")
+	}
+
+	plain := errors.New("Tunnel could not be deleted")
+	if wrapAPIError(plain) != plain {
+		t.Error("expected errors without a status to be returned unchanged")
+	}
+	if wrapAPIError(nil) != nil {
+		t.Error("expected nil to stay nil")
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		header http.Header
+		want   apiError
+	}{
+		{
+			name: "deployments",
+			body: `{"statusCode":409,"error":"Conflict","message":"Name already in use","txId":"tx-1"}`,
+			want: apiError{Code: "Conflict", Message: "Name already in use", RequestID: "tx-1"},
+		},
+		{
+			name: "numeric code",
+			body: `{"code":4001,"message":"Invalid destination","requestId":"req-2"}`,
+			want: apiError{Code: "4001", Message: "Invalid destination", RequestID: "req-2"},
+		},
+		{
+			name:   "request ID header",
+			body:   `{"errorCode":"INVALID_CIDR","message":"bad network"}`,
+			header: http.Header{"X-Request-Id": []string{"hdr-3"}},
+			want:   apiError{Code: "INVALID_CIDR", Message: "bad network", RequestID: "hdr-3"},
+		},
+		{
+			name: "not JSON",
+			body: `<html>Bad Gateway</html>`,
+		},
+	}
+
+	for _, c := range cases {
+		got := newAPIError(409, c.body, c.header)
+		if got.Code != c.want.Code || got.Message != c.want.Message || got.RequestID != c.want.RequestID {
+			t.Errorf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+		if got.Body != c.body {
+			t.Errorf("%s: body not kept, got %q", c.name, got.Body)
+		}
+	}
+}
+
+func TestAddAPIError(t *testing.T) {
+	var diags diag.Diagnostics
+	err := fmt.Errorf("could not add new credential: %w", newAPIError(409, `{"error":"Conflict","message":"Name already in use","txId":"tx-1"}`, nil))
+	addAPIError(&diags, "Error Creating Umbrella Site", "Could not create Umbrella Site", err, apiErrorPaths{409: path.Root("name")})
+
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+	withPath, ok := diags[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("name")) {
+		t.Errorf("expected the diagnostic to point at name, got %#v", diags[0])
+	}
+	detail := diags[0].Detail()
+	for _, want := range []string{
+		"Could not create Umbrella Site: could not add new credential: Umbrella API returned 409 Conflict: Name already in use.",
+		"same name",
+		"Request ID: tx-1",
+	} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("expected detail to contain %q, got %q", want, detail)
+		}
+	}
+
+	diags = nil
+	addAPIError(&diags, "Error Reading Umbrella Site", "Could not read Umbrella Site ID 1", errors.New("connection refused"), apiErrorPaths{409: path.Root("name")})
+	if _, ok := diags[0].(diag.DiagnosticWithPath); ok {
+		t.Error("expected errors without a status to not point at an attribute")
+	}
+	if diags[0].Detail() != "Could not read Umbrella Site ID 1: connection refused" {
+		t.Errorf("unexpected detail %q", diags[0].Detail())
+	}
+}
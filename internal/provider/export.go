@@ -0,0 +1,290 @@
+package umbrellaprovider
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olegunza/umbrella-api-go/umbrella"
+)
+
+// ExportConfig holds the credentials and organization Export reads from.
+type ExportConfig struct {
+	Host      string
+	Apikey    string
+	Apisecret string
+	// OrgId selects a child organization. 0 exports the organization owning
+	// the credentials.
+	OrgId int64
+}
+
+// Export writes Terraform configuration for the sites, tunnels and virtual
+// appliances of an Umbrella organization to w. Every object gets a resource
+// block and an import block, so that applying the configuration brings the
+// organization under Terraform without changing it.
+func Export(ctx context.Context, w io.Writer, cfg ExportConfig) error {
+	if cfg.Host == "" {
+		return errors.New("missing Umbrella API host")
+	}
+	if cfg.Apikey == "" {
+		return errors.New("missing Umbrella API key")
+	}
+	if cfg.Apisecret == "" {
+		return errors.New("missing Umbrella API secret")
+	}
+
+	client, err := newAPIClient(ctx, cfg.Host, cfg.Apikey, cfg.Apisecret, cfg.OrgId, defaultRetryPolicy())
+	if err != nil {
+		return fmt.Errorf("unable to create Umbrella API client: %w", err)
+	}
+	client = client.withContext(ctx)
+
+	sites, err := client.GetSites(nil)
+	if err != nil {
+		return fmt.Errorf("could not list Umbrella Sites: %w", err)
+	}
+	tunnels, err := client.GetTunnels(nil)
+	if err != nil {
+		return fmt.Errorf("could not list Umbrella Tunnels: %w", err)
+	}
+	vas, err := client.GetVAs(nil)
+	if err != nil {
+		return fmt.Errorf("could not list Umbrella VAs: %w", err)
+	}
+
+	e := &exporter{
+		w:      bufio.NewWriter(w),
+		orgID:  cfg.OrgId,
+		labels: map[string]bool{},
+	}
+	e.export(sites, tunnels, vas)
+
+	return e.w.Flush()
+}
+
+// exporter renders exported objects as HCL.
+type exporter struct {
+	w     *bufio.Writer
+	orgID int64
+	// labels holds the resource names already used, keyed by address.
+	labels map[string]bool
+	// siteOrigins and siteIDs map the origin ID and the site ID of every
+	// exported site to the address of its resource.
+	siteOrigins map[int64]string
+	siteIDs     map[int64]string
+}
+
+func (e *exporter) export(sites []umbrella.Site, tunnels []umbrella.NetworkTunnel, vas []umbrella.VA) {
+	sort.Slice(sites, func(i, j int) bool { return sites[i].Siteid < sites[j].Siteid })
+	sort.Slice(tunnels, func(i, j int) bool { return tunnels[i].Id < tunnels[j].Id })
+	sort.Slice(vas, func(i, j int) bool { return vas[i].OriginId < vas[j].OriginId })
+
+	e.siteOrigins = map[int64]string{}
+	e.siteIDs = map[int64]string{}
+
+	for _, site := range sites {
+		address := e.address("umbrella_site", site.Name)
+		e.siteOrigins[site.Originid] = address
+		e.siteIDs[int64(site.Siteid)] = address
+
+		attrs := []hclAttribute{
+			{"name", hclString(site.Name)},
+		}
+		e.resource(address, attrs, int64(site.Siteid))
+	}
+
+	for _, tunnel := range tunnels {
+		address := e.address("umbrella_tunnel", tunnel.Name)
+
+		attrs := []hclAttribute{
+			{"name", hclString(tunnel.Name)},
+			{"site_origin_id", e.siteReference(e.siteOrigins, tunnel.SiteOriginId, "origin_id")},
+			{"service_type", hclString(tunnel.ServiceType)},
+			{"client", hclObject([]hclAttribute{
+				{"device_type", hclString(tunnel.Client.DeviceType)},
+				{"authentication", hclObject([]hclAttribute{
+					{"type", hclString(tunnel.Client.Authentication.Type)},
+				})},
+			})},
+			{"transport", hclObject([]hclAttribute{
+				{"protocol", hclString(tunnel.Transport.Protocol)},
+			})},
+		}
+		if len(tunnel.NetworkCIDRs) > 0 {
+			attrs = append(attrs, hclAttribute{"network_cidrs", hclStringList(tunnel.NetworkCIDRs)})
+		}
+		fmt.Fprintln(e.w, "# The pre-shared key of the tunnel cannot be exported. Set")
+		fmt.Fprintln(e.w, "# client.authentication.parameters.secret only to rotate it.")
+		e.resource(address, attrs, tunnel.Id)
+	}
+
+	for _, va := range vas {
+		// Mirror the umbrella_va data source, which skips other appliance types.
+		if va.Type != "virtual_appliance" {
+			continue
+		}
+		address := e.address("umbrella_va", va.Name)
+
+		// discovery is left out, as it is only used on create and the import
+		// would otherwise be followed by an update.
+		attrs := []hclAttribute{
+			{"site_id", e.siteReference(e.siteIDs, va.SiteId, "site_id")},
+		}
+		e.resource(address, attrs, va.OriginId)
+	}
+}
+
+// siteReference refers to the attribute of the exported site with id, or
+// falls back to the literal id when the site was not exported.
+func (e *exporter) siteReference(sites map[int64]string, id int64, attribute string) string {
+	if address, ok := sites[id]; ok {
+		return address + "." + attribute
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// resource writes the resource block for address and the import block that
+// adopts the object with id.
+func (e *exporter) resource(address string, attrs []hclAttribute, id int64) {
+	importID := strconv.FormatInt(id, 10)
+	if e.orgID != 0 {
+		attrs = append(attrs, hclAttribute{"org_id", strconv.FormatInt(e.orgID, 10)})
+		importID = strconv.FormatInt(e.orgID, 10) + "/" + importID
+	}
+
+	resourceType, name, _ := strings.Cut(address, ".")
+	fmt.Fprintf(e.w, "resource %s %s {\n", hclString(resourceType), hclString(name))
+	writeHCLAttributes(e.w, attrs, 1)
+	fmt.Fprintln(e.w, "}")
+	fmt.Fprintln(e.w)
+
+	fmt.Fprintln(e.w, "import {")
+	writeHCLAttributes(e.w, []hclAttribute{
+		{"to", address},
+		{"id", hclString(importID)},
+	}, 1)
+	fmt.Fprintln(e.w, "}")
+	fmt.Fprintln(e.w)
+}
+
+// address returns a unique resource address of resourceType for an object
+// called name.
+func (e *exporter) address(resourceType, name string) string {
+	label := hclLabel(name)
+	if label == "" || label[0] >= '0' && label[0] <= '9' {
+		label = strings.TrimPrefix(resourceType, "umbrella_") + "_" + label
+		label = strings.TrimSuffix(label, "_")
+	}
+
+	address := resourceType + "." + label
+	for n := 2; e.labels[address]; n++ {
+		address = resourceType + "." + label + "_" + strconv.Itoa(n)
+	}
+	e.labels[address] = true
+
+	return address
+}
+
+// hclLabel turns name into a Terraform identifier: lower case letters,
+// digits and underscores.
+func hclLabel(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			underscore = false
+			continue
+		}
+		if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// hclAttribute is an attribute with its value already rendered as an HCL
+// expression.
+type hclAttribute struct {
+	name  string
+	value string
+}
+
+// writeHCLAttributes writes attrs at the given indentation level, aligning
+// the equals signs of consecutive single line attributes like terraform fmt.
+func writeHCLAttributes(w io.Writer, attrs []hclAttribute, level int) {
+	indent := strings.Repeat("  ", level)
+	for start := 0; start < len(attrs); {
+		end := start + 1
+		if !strings.Contains(attrs[start].value, "\n") {
+			for end < len(attrs) && !strings.Contains(attrs[end].value, "\n") {
+				end++
+			}
+		}
+
+		width := 0
+		for _, attr := range attrs[start:end] {
+			if len(attr.name) > width {
+				width = len(attr.name)
+			}
+		}
+		for _, attr := range attrs[start:end] {
+			value := strings.ReplaceAll(attr.value, "\n", "\n"+indent)
+			fmt.Fprintf(w, "%s%-*s = %s\n", indent, width, attr.name, value)
+		}
+
+		start = end
+	}
+}
+
+// hclObject renders attrs as an object expression spanning several lines.
+func hclObject(attrs []hclAttribute) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	writeHCLAttributes(&b, attrs, 1)
+	b.WriteString("}")
+	return b.String()
+}
+
+func hclStringList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = hclString(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// hclString renders s as a quoted HCL string, escaping template sequences
+// so that the value is taken literally.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			b.WriteRune(r)
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/olegunza/umbrella-api-go/umbrella"
+)
+
+// testAccExportFixture creates three sites, two of whose names map to the same
+// label, a tunnel, a virtual appliance and a connector in org.
+func testAccExportFixture(t *testing.T, fake *fakeUmbrella, org int64) (sites []umbrella.Site, tunnel *umbrella.NetworkTunnel, va umbrella.VA) {
+	t.Helper()
+
+	api, err := newAPIClient(context.Background(), fake.server.URL, "fake-key", "fake-secret", org, defaultRetryPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Branch 1", "branch-1", "10 Main St"} {
+		site, err := api.CreateSite(umbrella.Site{Name: name}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sites = append(sites, *site)
+	}
+
+	tunnel, err = api.CreateTunnel(umbrella.NetworkTunnel{
+		Name:         `branch "one" ${x}`,
+		SiteOriginId: sites[0].Originid,
+		Client: umbrella.TunnelClient{
+			DeviceType: "ASA",
+			Authentication: umbrella.TunnelAuth{
+				Type:       "PSK",
+				Parameters: umbrella.TunnelAuthParams{Id: "branch1", Secret: "Secret0123456789abc"},
+			},
+		},
+		Transport:    umbrella.TunnelTrans{Protocol: "IPSec"},
+		ServiceType:  "SIG",
+		NetworkCIDRs: []string{"10.10.0.0/24"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	va = fake.addVA(org, umbrella.VA{Name: "va-1", SiteId: int64(sites[2].Siteid)})
+	fake.addVA(org, umbrella.VA{Name: "connector-1", Type: "connector"})
+
+	return sites, tunnel, va
+}
+
+func testAccExport(t *testing.T, fake *fakeUmbrella, org int64) string {
+	t.Helper()
+
+	var out bytes.Buffer
+	err := Export(context.Background(), &out, ExportConfig{
+		Host:      fake.server.URL,
+		Apikey:    "fake-key",
+		Apisecret: "fake-secret",
+		OrgId:     org,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestExport(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	sites, tunnel, va := testAccExportFixture(t, fake, 1234)
+
+	config := testAccExport(t, fake, 1234)
+
+	for _, want := range []string{
+		`resource "umbrella_site" "branch_1" {`,
+		`resource "umbrella_site" "branch_1_2" {`,
+		`resource "umbrella_site" "site_10_main_st" {`,
+		`  name   = "10 Main St"` + "\n" + `  org_id = 1234`,
+		`  to = umbrella_site.branch_1` + "\n" + `  id = "1234/` + strconv.Itoa(sites[0].Siteid) + `"`,
+		`resource "umbrella_tunnel" "branch_one_x" {`,
+		`  name           = "branch \"one\" $${x}"`,
+		`  site_origin_id = umbrella_site.branch_1.origin_id`,
+		`  network_cidrs = ["10.10.0.0/24"]`,
+		`  id = "1234/` + strconv.FormatInt(tunnel.Id, 10) + `"`,
+		`resource "umbrella_va" "va_1" {`,
+		`  site_id = umbrella_site.site_10_main_st.site_id`,
+		`  id = "1234/` + strconv.FormatInt(va.OriginId, 10) + `"`,
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("exported configuration does not contain %q:\n%s", want, config)
+		}
+	}
+	if strings.Contains(config, "connector") {
+		t.Errorf("exported configuration contains an appliance that is not a VA:\n%s", config)
+	}
+}
+
+func TestExport_missingCredentials(t *testing.T) {
+	err := Export(context.Background(), &bytes.Buffer{}, ExportConfig{Host: "https://api.umbrella.com", Apikey: "key"})
+	if err == nil || !strings.Contains(err.Error(), "missing Umbrella API secret") {
+		t.Fatalf("expected missing secret error, got %v", err)
+	}
+}
+
+func TestHCLString(t *testing.T) {
+	cases := map[string]string{
+		`plain`:         `"plain"`,
+		`a "b" \c`:      `"a \"b\" \\c"`,
+		"line\nbreak":   `"line\nbreak"`,
+		`${var} %{if}`:  `"$${var} %%{if}"`,
+		`$ % {`:         `"$ % {"`,
+		"bell\x07":      `"bell\u0007"`,
+		`Zürich office`: `"Zürich office"`,
+	}
+
+	for in, want := range cases {
+		if got := hclString(in); got != want {
+			t.Errorf("hclString(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+// TestAccExport applies an exported configuration and checks that it only
+// imports the existing objects.
+func TestAccExport(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	sites, tunnel, va := testAccExportFixture(t, fake, 0)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Forget the requests that set up the fixture.
+				PreConfig: fake.clearRequests,
+				Config:    fake.providerConfig() + testAccExport(t, fake, 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_site.branch_1", "site_id", strconv.Itoa(sites[0].Siteid)),
+					resource.TestCheckResourceAttr("umbrella_site.branch_1_2", "site_id", strconv.Itoa(sites[1].Siteid)),
+					resource.TestCheckResourceAttr("umbrella_site.site_10_main_st", "site_id", strconv.Itoa(sites[2].Siteid)),
+					resource.TestCheckResourceAttr("umbrella_tunnel.branch_one_x", "id", strconv.FormatInt(tunnel.Id, 10)),
+					resource.TestCheckResourceAttr("umbrella_va.va_1", "origin_id", strconv.FormatInt(va.OriginId, 10)),
+					func(*terraform.State) error {
+						if names := fake.siteNames(0); len(names) != len(sites) {
+							return fmt.Errorf("expected %d sites, got %v", len(sites), names)
+						}
+						return nil
+					},
+					testAccCheckNoRequests(fake, "POST", "/deployments/"),
+					testAccCheckNoRequests(fake, "PUT", "/"),
+					testAccCheckNoRequests(fake, "PATCH", "/"),
+					testAccCheckNoRequests(fake, "DELETE", "/"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,1580 @@
+package umbrellaprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/olegunza/umbrella-api-go/umbrella"
+)
+
+// fakeUmbrella is an in-memory stand-in for the Umbrella API. It serves the
+// token, sites, networks, internal networks, internal domains, tunnels,
+// virtual appliances, roaming computers, tags, device settings, network
+// devices, policies, datacenters and destination list endpoints so that acceptance tests can run without a real tenant.
+//
+// Every object belongs to the organization named in the token request, which
+// mirrors how child-organization tokens behave on a provider console.
+type fakeUmbrella struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	nextID       int64
+	owners       map[int64]int64
+	sites        map[int64]umbrella.Site
+	tunnels      map[int64]umbrella.NetworkTunnel
+	tunnelKeys   map[int64][]TunnelCredential
+	tunnelStates map[int64]TunnelState
+	vas          map[int64]umbrella.VA
+	networks     map[int64]Network
+	roaming      map[int64]RoamingComputer
+	tags         map[int64]Tag
+	tagDevices   map[int64]map[int64]bool
+	tagBatches   int
+	netDevices   map[int64]NetworkDevice
+	swgSettings  map[int64]DeviceSetting
+	policies     map[int64]fakePolicy
+	attached     map[int64]map[int64]bool
+	dcs          umbrella.DCList
+	internalNets map[int64]InternalNetwork
+	internalDoms map[int64]InternalDomain
+	destLists    map[int64]DestinationList
+	destinations map[int64][]Destination
+	destBatches  int
+	// dedupDestinations makes adding a destination that is already in the
+	// list keep the existing entry, comment included.
+	dedupDestinations bool
+	faults            []*fakeFault
+	stalls            []*fakeFault
+	requests          []string
+	pendingVAs        []pendingVA
+}
+
+// pendingVA is a virtual appliance that registers once the VA list has been
+// requested lists times, as if it was still booting before then.
+type pendingVA struct {
+	org   int64
+	va    umbrella.VA
+	lists int
+}
+
+// fakePolicy is a policy together with its type, which the API only takes
+// as a list filter.
+type fakePolicy struct {
+	Policy
+	policyType string
+}
+
+// fakeFault makes the next times requests matching method and path prefix
+// fail with status, or wait for delay before being served, after letting skip
+// of them through.
+type fakeFault struct {
+	method     string
+	path       string
+	status     int
+	skip       int
+	times      int
+	retryAfter string
+	delay      time.Duration
+}
+
+const fakeTokenPrefix = "fake-token-"
+
+func newFakeUmbrella(t *testing.T) *fakeUmbrella {
+	f := &fakeUmbrella{
+		nextID:       1000,
+		owners:       map[int64]int64{},
+		sites:        map[int64]umbrella.Site{},
+		tunnels:      map[int64]umbrella.NetworkTunnel{},
+		tunnelKeys:   map[int64][]TunnelCredential{},
+		tunnelStates: map[int64]TunnelState{},
+		vas:          map[int64]umbrella.VA{},
+		networks:     map[int64]Network{},
+		roaming:      map[int64]RoamingComputer{},
+		tags:         map[int64]Tag{},
+		tagDevices:   map[int64]map[int64]bool{},
+		netDevices:   map[int64]NetworkDevice{},
+		swgSettings:  map[int64]DeviceSetting{},
+		policies:     map[int64]fakePolicy{},
+		attached:     map[int64]map[int64]bool{},
+		internalNets: map[int64]InternalNetwork{},
+		internalDoms: map[int64]InternalDomain{},
+		destLists:    map[int64]DestinationList{},
+		destinations: map[int64][]Destination{},
+		dcs: umbrella.DCList{
+			Continents: []umbrella.Continent{
+				{
+					Name: "Europe",
+					Cities: []umbrella.City{
+						{Name: "Amsterdam", Dc: "ams1", Latitude: "52.37", Longitude: "4.89", Range: "146.112.67.0/24", Fqdn: "sig-ams1.umbrella.com"},
+					},
+				},
+				{
+					Name: "North America",
+					Cities: []umbrella.City{
+						{Name: "Ashburn", Dc: "iad1", Latitude: "39.04", Longitude: "-77.49", Range: "146.112.82.0/24", Fqdn: "sig-iad1.umbrella.com"},
+					},
+				},
+			},
+		},
+	}
+
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+// providerConfig returns a provider block that points at the fake API.
+func (f *fakeUmbrella) providerConfig() string {
+	return fmt.Sprintf(`
+provider "umbrella" {
+  host      = %q
+  apikey    = "fake-key"
+  apisecret = "fake-secret"
+}
+`, f.server.URL)
+}
+
+// fail makes the next times requests for method and a path starting with
+// prefix fail with status.
+func (f *fakeUmbrella) fail(method, prefix string, status, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = append(f.faults, &fakeFault{method: method, path: prefix, status: status, times: times})
+}
+
+// failAfter lets the next skip requests for method and a path starting with
+// prefix through, and makes the times requests after them fail with status.
+func (f *fakeUmbrella) failAfter(method, prefix string, status, skip, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = append(f.faults, &fakeFault{method: method, path: prefix, status: status, skip: skip, times: times})
+}
+
+// stall delays the next times responses for method and prefix by delay, the
+// way a slow Umbrella operation would.
+func (f *fakeUmbrella) stall(method, prefix string, delay time.Duration, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stalls = append(f.stalls, &fakeFault{method: method, path: prefix, delay: delay, times: times})
+}
+
+// throttle makes the next times requests for method and prefix fail with 429
+// and the given Retry-After header.
+func (f *fakeUmbrella) throttle(method, prefix string, times int, retryAfter string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = append(f.faults, &fakeFault{method: method, path: prefix, status: http.StatusTooManyRequests, times: times, retryAfter: retryAfter})
+}
+
+// requested returns the positions, in the order they were received, of the
+// requests for method and a path starting with prefix.
+func (f *fakeUmbrella) requested(method, prefix string) []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var positions []int
+	for i, request := range f.requests {
+		if strings.HasPrefix(request, method+" "+prefix) {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// clearRequests forgets the requests received so far, so that requested only
+// reports the ones that follow.
+func (f *fakeUmbrella) clearRequests() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = nil
+}
+
+// addVA registers a virtual appliance in org, the way an appliance shows up
+// once it is deployed and has connected to Umbrella.
+func (f *fakeUmbrella) addVA(org int64, va umbrella.VA) umbrella.VA {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if va.OriginId == 0 {
+		va.OriginId = f.newID(org)
+	} else {
+		f.owners[va.OriginId] = org
+	}
+	if va.Type == "" {
+		va.Type = "virtual_appliance"
+	}
+	va.CreatedAt = fakeNow()
+	va.ModifiedAt = va.CreatedAt
+	va.StateUpdatedAt = va.CreatedAt
+	f.vas[va.OriginId] = va
+
+	return va
+}
+
+// addRoamingComputer registers a roaming computer in org, the way a computer
+// shows up once the roaming module is installed on it.
+func (f *fakeUmbrella) addRoamingComputer(org int64, computer RoamingComputer) RoamingComputer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	computer.OriginId = f.newID(org)
+	if computer.DeviceId == "" {
+		computer.DeviceId = fmt.Sprintf("%016x", computer.OriginId)
+	}
+	computer.Type = fakeDefault(computer.Type, "anyconnect")
+	computer.Status = fakeDefault(computer.Status, "Protected")
+	f.roaming[computer.OriginId] = computer
+
+	return computer
+}
+
+// addTag creates a tag in org.
+func (f *fakeUmbrella) addTag(org int64, name string) Tag {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tag := Tag{Id: f.newID(org), Name: name, CreatedAt: fakeNow()}
+	tag.ModifiedAt = tag.CreatedAt
+	f.tags[tag.Id] = tag
+
+	return tag
+}
+
+// addRoamingComputers registers n roaming computers in org and returns their
+// origin IDs.
+func (f *fakeUmbrella) addRoamingComputers(org int64, n int) []int64 {
+	originIDs := make([]int64, n)
+	for i := range originIDs {
+		originIDs[i] = f.addRoamingComputer(org, RoamingComputer{Name: fmt.Sprintf("LAPTOP-%04d", i)}).OriginId
+	}
+	return originIDs
+}
+
+func (f *fakeUmbrella) tagCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.tags)
+}
+
+// taggedCount returns the number of devices with the tag tagID.
+func (f *fakeUmbrella) taggedCount(tagID int64) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.tagDevices[tagID])
+}
+
+// addPolicy creates a policy of policyType, "dns" or "web", in org.
+func (f *fakeUmbrella) addPolicy(org int64, policyType, name string) Policy {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	policy := Policy{Id: f.newID(org), OrganizationId: org, Name: name, CreatedAt: fakeNow()}
+	f.policies[policy.Id] = fakePolicy{Policy: policy, policyType: policyType}
+
+	return policy
+}
+
+// isAttached reports whether the identity with originID is attached to the
+// policy with policyID.
+func (f *fakeUmbrella) isAttached(policyID, originID int64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.attached[policyID][originID]
+}
+
+// setRoamingComputerStatus changes the status the roaming computer with
+// deviceID reports, as if the roaming module was switched off or removed.
+func (f *fakeUmbrella) setRoamingComputerStatus(deviceID, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for originID, computer := range f.roaming {
+		if computer.DeviceId == deviceID {
+			computer.Status = status
+			f.roaming[originID] = computer
+		}
+	}
+}
+
+// hasRoamingComputer reports whether the roaming computer with deviceID is
+// registered.
+func (f *fakeUmbrella) hasRoamingComputer(deviceID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, computer := range f.roaming {
+		if computer.DeviceId == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// remove deletes the object with id, as if it was deleted outside Terraform.
+func (f *fakeUmbrella) remove(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	objectID, _ := strconv.ParseInt(id, 10, 64)
+	delete(f.sites, objectID)
+	delete(f.tunnels, objectID)
+	delete(f.vas, objectID)
+	delete(f.networks, objectID)
+	delete(f.roaming, objectID)
+	delete(f.netDevices, objectID)
+	delete(f.policies, objectID)
+	delete(f.internalNets, objectID)
+	delete(f.internalDoms, objectID)
+	delete(f.destLists, objectID)
+	delete(f.destinations, objectID)
+	delete(f.owners, objectID)
+}
+
+// siteNames returns the names of the sites that exist in org.
+func (f *fakeUmbrella) siteNames(org int64) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var names []string
+	for id, site := range f.sites {
+		if f.owners[id] == org {
+			names = append(names, site.Name)
+		}
+	}
+	return names
+}
+
+// addVALater registers va in org after the VA list has been requested lists
+// times.
+func (f *fakeUmbrella) addVALater(org int64, va umbrella.VA, lists int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pendingVAs = append(f.pendingVAs, pendingVA{org: org, va: va, lists: lists})
+}
+
+// hasVA reports whether the virtual appliance with originID is registered.
+func (f *fakeUmbrella) hasVA(originID int64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.vas[originID]
+	return ok
+}
+
+// internalNetworkCount returns the number of internal networks in every org.
+func (f *fakeUmbrella) internalNetworkCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.internalNets)
+}
+
+// internalDomainCount returns the number of internal domains in every org.
+func (f *fakeUmbrella) internalDomainCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.internalDoms)
+}
+
+func (f *fakeUmbrella) networkCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.networks)
+}
+
+func (f *fakeUmbrella) networkDeviceCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.netDevices)
+}
+
+// destinationListCount returns the number of destination lists in every org.
+func (f *fakeUmbrella) destinationListCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.destLists)
+}
+
+// setNetworkAddress changes the address of the network with id, the way the
+// dynamic IP updater does for dynamic networks.
+func (f *fakeUmbrella) setNetworkAddress(id string, ipAddress string, prefixLength int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	networkID, _ := strconv.ParseInt(id, 10, 64)
+	network := f.networks[networkID]
+	network.IpAddress, network.PrefixLength = ipAddress, prefixLength
+	f.networks[networkID] = network
+}
+
+func fakeNow() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func (f *fakeUmbrella) newID(org int64) int64 {
+	f.nextID++
+	f.owners[f.nextID] = org
+	return f.nextID
+}
+
+func (f *fakeUmbrella) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if delay := f.stallFor(r); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+	if f.injectFault(w, r) {
+		return
+	}
+
+	if r.URL.Path == "/auth/v2/token" {
+		f.serveToken(w, r)
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !strings.HasPrefix(token, fakeTokenPrefix) {
+		writeFakeError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+		return
+	}
+	org, _ := strconv.ParseInt(strings.TrimPrefix(token, fakeTokenPrefix), 10, 64)
+
+	prefix := "/deployments/v2/"
+	if strings.HasPrefix(r.URL.Path, "/policies/v2/") {
+		prefix = "/policies/v2/"
+	}
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, prefix), "/", 3)
+	collection, hasID, sub := parts[0], len(parts) > 1, ""
+	if len(parts) == 3 {
+		sub = parts[2]
+	}
+	// Roaming computers are addressed by their device ID rather than by
+	// origin ID.
+	if collection == "roamingcomputers" && sub == "" {
+		f.serveRoamingComputers(w, r, org, parts[1:])
+		return
+	}
+	if collection == "deviceSettings" && hasID {
+		f.serveDeviceSettings(w, r, org, parts[1], sub)
+		return
+	}
+
+	var objectID int64
+	if hasID {
+		var err error
+		objectID, err = strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || f.owners[objectID] != org {
+			writeFakeError(w, http.StatusNotFound, "not found")
+			return
+		}
+	}
+
+	if sub != "" && collection != "destinationlists" && collection != "tunnels" && collection != "tags" && collection != "policies" {
+		writeFakeError(w, http.StatusNotFound, "no such endpoint")
+		return
+	}
+
+	switch collection {
+	case "destinationlists":
+		f.serveDestinationLists(w, r, org, objectID, hasID, sub)
+	case "sites":
+		f.serveSites(w, r, org, objectID, hasID)
+	case "networks":
+		f.serveNetworks(w, r, org, objectID, hasID)
+	case "internalnetworks":
+		f.serveInternalNetworks(w, r, org, objectID, hasID)
+	case "internaldomains":
+		f.serveInternalDomains(w, r, org, objectID, hasID)
+	case "tunnels":
+		if sub == "state" {
+			f.serveTunnelState(w, r, objectID)
+			return
+		}
+		if sub != "" {
+			f.serveTunnelKeys(w, r, org, objectID, sub)
+			return
+		}
+		f.serveTunnels(w, r, org, objectID, hasID)
+	case "virtualappliances":
+		f.serveVAs(w, r, org, objectID, hasID)
+	case "policies":
+		f.servePolicies(w, r, org, objectID, hasID, sub)
+	case "networkdevices":
+		f.serveNetworkDevices(w, r, org, objectID, hasID)
+	case "tags":
+		f.serveTags(w, r, org, objectID, hasID, sub)
+	case "datacenters":
+		writeFakeJSON(w, http.StatusOK, f.dcs)
+	default:
+		writeFakeError(w, http.StatusNotFound, "no such endpoint")
+	}
+}
+
+func (f *fakeUmbrella) stallFor(r *http.Request) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, stall := range f.stalls {
+		if stall.times == 0 || stall.method != r.Method || !strings.HasPrefix(r.URL.Path, stall.path) {
+			continue
+		}
+		stall.times--
+		return stall.delay
+	}
+	return 0
+}
+
+func (f *fakeUmbrella) injectFault(w http.ResponseWriter, r *http.Request) bool {
+	for _, fault := range f.faults {
+		if fault.times == 0 || fault.method != r.Method || !strings.HasPrefix(r.URL.Path, fault.path) {
+			continue
+		}
+		if fault.skip > 0 {
+			fault.skip--
+			continue
+		}
+		fault.times--
+		if fault.retryAfter != "" {
+			w.Header().Set("Retry-After", fault.retryAfter)
+		}
+		writeFakeError(w, fault.status, "injected fault")
+		return true
+	}
+	return false
+}
+
+func (f *fakeUmbrella) serveToken(w http.ResponseWriter, r *http.Request) {
+	if user, pass, ok := r.BasicAuth(); !ok || user != "fake-key" || pass != "fake-secret" {
+		writeFakeError(w, http.StatusUnauthorized, "invalid client credentials")
+		return
+	}
+
+	org := r.Header.Get(orgIDHeader)
+	if org == "" {
+		org = "0"
+	}
+
+	writeFakeJSON(w, http.StatusOK, tokenResponse{
+		TokenType:   "bearer",
+		AccessToken: fakeTokenPrefix + org,
+		ExpiresIn:   3600,
+	})
+}
+
+func (f *fakeUmbrella) serveSites(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool) {
+	switch {
+	case !hasID && r.Method == "GET":
+		sites := []umbrella.Site{}
+		for siteID, site := range f.sites {
+			if f.owners[siteID] == org {
+				sites = append(sites, site)
+			}
+		}
+		writeFakeJSON(w, http.StatusOK, sites)
+	case !hasID && r.Method == "POST":
+		var site umbrella.Site
+		if !decodeFakeBody(w, r, &site) {
+			return
+		}
+		for siteID, existing := range f.sites {
+			if f.owners[siteID] == org && existing.Name == site.Name {
+				writeFakeError(w, http.StatusConflict, "A site with the name "+site.Name+" already exists")
+				return
+			}
+		}
+		site.Siteid = int(f.newID(org))
+		site.Originid = int64(site.Siteid)
+		site.Type = "site"
+		site.Createdat = fakeNow()
+		site.Modifiedat = site.Createdat
+		f.sites[site.Originid] = site
+		writeFakeJSON(w, http.StatusOK, site)
+	case hasID && r.Method == "GET":
+		site, ok := f.sites[id]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "site not found")
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, site)
+	case hasID && r.Method == "PUT":
+		site, ok := f.sites[id]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "site not found")
+			return
+		}
+		var update umbrella.Site
+		if !decodeFakeBody(w, r, &update) {
+			return
+		}
+		site.Name = update.Name
+		site.Modifiedat = fakeNow()
+		f.sites[id] = site
+		writeFakeJSON(w, http.StatusOK, site)
+	case hasID && r.Method == "DELETE":
+		if _, ok := f.sites[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "site not found")
+			return
+		}
+		delete(f.sites, id)
+		delete(f.owners, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeUmbrella) serveNetworks(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool) {
+	if hasID {
+		if _, ok := f.networks[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "network not found")
+			return
+		}
+	}
+
+	// validate rejects networks that the real API refuses to register.
+	validate := func(network *Network) bool {
+		if !network.IsDynamic && (network.IpAddress == "" || network.PrefixLength < 29 || network.PrefixLength > 32) {
+			writeFakeError(w, http.StatusBadRequest, "ipAddress and a prefixLength between 29 and 32 are required")
+			return false
+		}
+		for networkID, existing := range f.networks {
+			if networkID != id && f.owners[networkID] == org && existing.Name == network.Name {
+				writeFakeError(w, http.StatusConflict, "A network with the name "+network.Name+" already exists")
+				return false
+			}
+		}
+		network.Status = fakeDefault(network.Status, "OPEN")
+		return true
+	}
+
+	switch {
+	case !hasID && r.Method == "GET":
+		networks := []Network{}
+		for networkID, network := range f.networks {
+			if f.owners[networkID] == org {
+				networks = append(networks, network)
+			}
+		}
+		writeFakeJSON(w, http.StatusOK, networks)
+	case !hasID && r.Method == "POST":
+		var network Network
+		if !decodeFakeBody(w, r, &network) || !validate(&network) {
+			return
+		}
+		network.OriginId = f.newID(org)
+		network.IsVerified = false
+		network.CreatedAt = fakeNow()
+		f.networks[network.OriginId] = network
+		writeFakeJSON(w, http.StatusOK, network)
+	case hasID && r.Method == "GET":
+		writeFakeJSON(w, http.StatusOK, f.networks[id])
+	case hasID && r.Method == "PUT":
+		var update Network
+		if !decodeFakeBody(w, r, &update) || !validate(&update) {
+			return
+		}
+		network := f.networks[id]
+		update.OriginId = network.OriginId
+		update.IsVerified = network.IsVerified
+		update.CreatedAt = network.CreatedAt
+		if update.IsDynamic && update.IpAddress == "" {
+			update.IpAddress, update.PrefixLength = network.IpAddress, network.PrefixLength
+		}
+		f.networks[id] = update
+		writeFakeJSON(w, http.StatusOK, update)
+	case hasID && r.Method == "DELETE":
+		delete(f.networks, id)
+		delete(f.owners, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// roamingComputer returns the roaming computer of org with deviceID, with
+// its tags filled in.
+func (f *fakeUmbrella) roamingComputer(org int64, deviceID string) (RoamingComputer, bool) {
+	for originID, computer := range f.roaming {
+		if computer.DeviceId == deviceID && f.owners[originID] == org {
+			return f.withTags(computer), true
+		}
+	}
+	return RoamingComputer{}, false
+}
+
+func (f *fakeUmbrella) withTags(computer RoamingComputer) RoamingComputer {
+	computer.Tags = nil
+	for tagID, devices := range f.tagDevices {
+		if devices[computer.OriginId] {
+			computer.Tags = append(computer.Tags, f.tags[tagID])
+		}
+	}
+	sort.Slice(computer.Tags, func(i, j int) bool { return computer.Tags[i].Id < computer.Tags[j].Id })
+	return computer
+}
+
+func (f *fakeUmbrella) serveRoamingComputers(w http.ResponseWriter, r *http.Request, org int64, ids []string) {
+	if len(ids) == 0 {
+		if r.Method != "GET" {
+			writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		page, _ := strconv.Atoi(fakeDefault(r.URL.Query().Get("page"), "1"))
+		limit, _ := strconv.Atoi(fakeDefault(r.URL.Query().Get("limit"), "100"))
+
+		computers := []RoamingComputer{}
+		for originID, computer := range f.roaming {
+			if f.owners[originID] == org {
+				computers = append(computers, f.withTags(computer))
+			}
+		}
+		sort.Slice(computers, func(i, j int) bool { return computers[i].OriginId < computers[j].OriginId })
+
+		start, end := (page-1)*limit, page*limit
+		if start > len(computers) {
+			start = len(computers)
+		}
+		if end > len(computers) {
+			end = len(computers)
+		}
+		writeFakeJSON(w, http.StatusOK, computers[start:end])
+		return
+	}
+
+	computer, ok := f.roamingComputer(org, ids[0])
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "roaming computer not found")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeFakeJSON(w, http.StatusOK, computer)
+	case "PUT":
+		var update RoamingComputer
+		if !decodeFakeBody(w, r, &update) {
+			return
+		}
+		if update.Name == "" {
+			writeFakeError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		stored := f.roaming[computer.OriginId]
+		stored.Name = update.Name
+		f.roaming[computer.OriginId] = stored
+		writeFakeJSON(w, http.StatusOK, f.withTags(stored))
+	case "DELETE":
+		delete(f.roaming, computer.OriginId)
+		delete(f.owners, computer.OriginId)
+		delete(f.swgSettings, computer.OriginId)
+		for _, devices := range f.tagDevices {
+			delete(devices, computer.OriginId)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// serveDeviceSettings serves the set, list and remove actions of the
+// SWGEnabled device setting of roaming computers.
+func (f *fakeUmbrella) serveDeviceSettings(w http.ResponseWriter, r *http.Request, org int64, setting, action string) {
+	if setting != swgEnabledSetting || r.Method != "POST" {
+		writeFakeError(w, http.StatusNotFound, "no such endpoint")
+		return
+	}
+
+	var request deviceSettingsRequest
+	if !decodeFakeBody(w, r, &request) {
+		return
+	}
+	if len(request.OriginIds) > deviceSettingBatchSize {
+		writeFakeError(w, http.StatusBadRequest, "too many devices")
+		return
+	}
+
+	if action == "list" {
+		settings := []DeviceSetting{}
+		for _, originID := range request.OriginIds {
+			if setting, ok := f.swgSettings[originID]; ok && f.owners[originID] == org {
+				settings = append(settings, setting)
+			}
+		}
+		writeFakeJSON(w, http.StatusOK, settings)
+		return
+	}
+	if action != "set" && action != "remove" {
+		writeFakeError(w, http.StatusNotFound, "no such endpoint")
+		return
+	}
+	if action == "set" && request.Value != "0" && request.Value != "1" {
+		writeFakeError(w, http.StatusBadRequest, "value must be 0 or 1")
+		return
+	}
+
+	response := deviceSettingsResponse{TotalCount: int64(len(request.OriginIds))}
+	for _, originID := range request.OriginIds {
+		if _, ok := f.roaming[originID]; !ok || f.owners[originID] != org {
+			response.FailCount++
+			response.Items = append(response.Items, DeviceSettingResult{OriginId: originID, Code: http.StatusNotFound, Message: "device not found"})
+			continue
+		}
+		if action == "set" {
+			f.swgSettings[originID] = DeviceSetting{OriginId: originID, Name: setting, Value: request.Value, ModifiedAt: fakeNow()}
+		} else {
+			delete(f.swgSettings, originID)
+		}
+		response.SuccessCount++
+		response.Items = append(response.Items, DeviceSettingResult{OriginId: originID, Code: http.StatusOK})
+	}
+	writeFakeJSON(w, http.StatusOK, response)
+}
+
+// swgSetting returns the SWGEnabled override of the device with originID, or
+// "" when it has none.
+func (f *fakeUmbrella) swgSetting(originID int64) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.swgSettings[originID].Value
+}
+
+// setSWGSetting overrides the SWGEnabled setting of the device with originID
+// outside Terraform. An empty value clears the override.
+func (f *fakeUmbrella) setSWGSetting(originID int64, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if value == "" {
+		delete(f.swgSettings, originID)
+		return
+	}
+	f.swgSettings[originID] = DeviceSetting{OriginId: originID, Name: swgEnabledSetting, Value: value, ModifiedAt: fakeNow()}
+}
+
+func (f *fakeUmbrella) serveTags(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool, sub string) {
+	if hasID {
+		if _, ok := f.tags[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "tag not found")
+			return
+		}
+	}
+
+	switch {
+	case !hasID && r.Method == "GET":
+		tags := []Tag{}
+		for tagID, tag := range f.tags {
+			if f.owners[tagID] == org {
+				tags = append(tags, tag)
+			}
+		}
+		writeFakeJSON(w, http.StatusOK, tags)
+	case !hasID && r.Method == "POST":
+		var tag Tag
+		if !decodeFakeBody(w, r, &tag) {
+			return
+		}
+		if tag.Name == "" {
+			writeFakeError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		for tagID, existing := range f.tags {
+			if f.owners[tagID] == org && existing.Name == tag.Name {
+				writeFakeError(w, http.StatusConflict, "A tag with the name "+tag.Name+" already exists")
+				return
+			}
+		}
+		tag.Id = f.newID(org)
+		tag.CreatedAt = fakeNow()
+		tag.ModifiedAt = tag.CreatedAt
+		f.tags[tag.Id] = tag
+		writeFakeJSON(w, http.StatusOK, tag)
+	case hasID && sub == "" && r.Method == "DELETE":
+		delete(f.tags, id)
+		delete(f.tagDevices, id)
+		delete(f.owners, id)
+		w.WriteHeader(http.StatusNoContent)
+	case hasID && sub == "devices" && (r.Method == "POST" || r.Method == "DELETE"):
+		var change tagDevicesRequest
+		if !decodeFakeBody(w, r, &change) {
+			return
+		}
+		if len(change.AddOrigins)+len(change.RemoveOrigins) > tagDeviceBatchSize {
+			writeFakeError(w, http.StatusBadRequest, "too many devices")
+			return
+		}
+		for _, originID := range append(change.AddOrigins, change.RemoveOrigins...) {
+			if _, ok := f.roaming[originID]; !ok || f.owners[originID] != org {
+				writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("unknown origin %d", originID))
+				return
+			}
+		}
+		if f.tagDevices[id] == nil {
+			f.tagDevices[id] = map[int64]bool{}
+		}
+		for _, originID := range change.AddOrigins {
+			f.tagDevices[id][originID] = true
+		}
+		for _, originID := range change.RemoveOrigins {
+			delete(f.tagDevices[id], originID)
+		}
+		f.tagBatches++
+		writeFakeJSON(w, http.StatusOK, f.tags[id])
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeUmbrella) servePolicies(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool, sub string) {
+	if hasID {
+		if _, ok := f.policies[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "policy not found")
+			return
+		}
+	}
+
+	switch {
+	case !hasID && r.Method == "GET":
+		policyType := r.URL.Query().Get("type")
+		if policyType != "dns" && policyType != "web" {
+			writeFakeError(w, http.StatusBadRequest, "type must be dns or web")
+			return
+		}
+		page, _ := strconv.Atoi(fakeDefault(r.URL.Query().Get("page"), "1"))
+		limit, _ := strconv.Atoi(fakeDefault(r.URL.Query().Get("limit"), "100"))
+
+		policies := []Policy{}
+		for policyID, policy := range f.policies {
+			if f.owners[policyID] == org && policy.policyType == policyType {
+				policies = append(policies, policy.Policy)
+			}
+		}
+		sort.Slice(policies, func(i, j int) bool { return policies[i].Id < policies[j].Id })
+
+		start, end := (page-1)*limit, page*limit
+		if start > len(policies) {
+			start = len(policies)
+		}
+		if end > len(policies) {
+			end = len(policies)
+		}
+		writeFakeJSON(w, http.StatusOK, policies[start:end])
+	case hasID && strings.HasPrefix(sub, "identities/") && (r.Method == "PUT" || r.Method == "DELETE"):
+		originID, err := strconv.ParseInt(strings.TrimPrefix(sub, "identities/"), 10, 64)
+		if err != nil || f.owners[originID] != org {
+			writeFakeError(w, http.StatusBadRequest, "unknown identity "+strings.TrimPrefix(sub, "identities/"))
+			return
+		}
+		if r.Method == "DELETE" {
+			if !f.attached[id][originID] {
+				writeFakeError(w, http.StatusNotFound, "identity is not attached to the policy")
+				return
+			}
+			delete(f.attached[id], originID)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if f.attached[id] == nil {
+			f.attached[id] = map[int64]bool{}
+		}
+		f.attached[id][originID] = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeUmbrella) serveNetworkDevices(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool) {
+	if hasID {
+		if _, ok := f.netDevices[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "network device not found")
+			return
+		}
+	}
+
+	switch {
+	case !hasID && r.Method == "GET":
+		devices := []NetworkDevice{}
+		for deviceID, device := range f.netDevices {
+			if f.owners[deviceID] == org {
+				devices = append(devices, device)
+			}
+		}
+		writeFakeJSON(w, http.StatusOK, devices)
+	case !hasID && r.Method == "POST":
+		var device NetworkDevice
+		if !decodeFakeBody(w, r, &device) {
+			return
+		}
+		mac, err := net.ParseMAC(device.MacAddress)
+		if device.Model == "" || device.Name == "" || device.SerialNumber == "" || err != nil {
+			writeFakeError(w, http.StatusBadRequest, "model, macAddress, name and serialNumber are required")
+			return
+		}
+		for deviceID, existing := range f.netDevices {
+			if f.owners[deviceID] == org && existing.SerialNumber == device.SerialNumber {
+				writeFakeError(w, http.StatusConflict, "A network device with the serial number "+device.SerialNumber+" already exists")
+				return
+			}
+		}
+		// Like the real API, the address comes back in canonical form.
+		device.MacAddress = mac.String()
+		device.OriginId = f.newID(org)
+		device.DeviceId = fmt.Sprintf("%016x", device.OriginId)
+		device.OrganizationId = org
+		device.CreatedAt = fakeNow()
+		f.netDevices[device.OriginId] = device
+		writeFakeJSON(w, http.StatusOK, device)
+	case hasID && r.Method == "GET":
+		writeFakeJSON(w, http.StatusOK, f.netDevices[id])
+	case hasID && r.Method == "PATCH":
+		var update NetworkDevice
+		if !decodeFakeBody(w, r, &update) {
+			return
+		}
+		if update.Name == "" {
+			writeFakeError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		device := f.netDevices[id]
+		device.Name = update.Name
+		f.netDevices[id] = device
+		writeFakeJSON(w, http.StatusOK, device)
+	case hasID && r.Method == "DELETE":
+		delete(f.netDevices, id)
+		delete(f.owners, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeUmbrella) serveInternalNetworks(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool) {
+	if hasID {
+		if _, ok := f.internalNets[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "internal network not found")
+			return
+		}
+	}
+
+	// resolve fills in the names of the associated site or tunnel, and
+	// rejects associations with objects that do not exist in org.
+	resolve := func(network *InternalNetwork) bool {
+		network.SiteName, network.TunnelName, network.NetworkName = "", "", ""
+		switch {
+		case network.SiteId != 0:
+			site, ok := f.sites[network.SiteId]
+			if !ok || f.owners[network.SiteId] != org {
+				writeFakeError(w, http.StatusBadRequest, "site not found")
+				return false
+			}
+			network.SiteName = site.Name
+		case network.TunnelId != 0:
+			tunnel, ok := f.tunnels[network.TunnelId]
+			if !ok || f.owners[network.TunnelId] != org {
+				writeFakeError(w, http.StatusBadRequest, "tunnel not found")
+				return false
+			}
+			network.TunnelName = tunnel.Name
+		case network.NetworkId == 0:
+			writeFakeError(w, http.StatusBadRequest, "one of siteId, networkId or tunnelId is required")
+			return false
+		}
+		return true
+	}
+
+	switch {
+	case !hasID && r.Method == "POST":
+		var network InternalNetwork
+		if !decodeFakeBody(w, r, &network) || !resolve(&network) {
+			return
+		}
+		network.OriginId = f.newID(org)
+		network.CreatedAt = fakeNow()
+		network.ModifiedAt = network.CreatedAt
+		f.internalNets[network.OriginId] = network
+		writeFakeJSON(w, http.StatusOK, network)
+	case hasID && r.Method == "GET":
+		writeFakeJSON(w, http.StatusOK, f.internalNets[id])
+	case hasID && r.Method == "PUT":
+		var update InternalNetwork
+		if !decodeFakeBody(w, r, &update) || !resolve(&update) {
+			return
+		}
+		network := f.internalNets[id]
+		update.OriginId = network.OriginId
+		update.CreatedAt = network.CreatedAt
+		update.ModifiedAt = fakeNow()
+		f.internalNets[id] = update
+		writeFakeJSON(w, http.StatusOK, update)
+	case hasID && r.Method == "DELETE":
+		delete(f.internalNets, id)
+		delete(f.owners, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeUmbrella) serveInternalDomains(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool) {
+	if hasID {
+		if _, ok := f.internalDoms[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "internal domain not found")
+			return
+		}
+	}
+
+	validSites := func(domain InternalDomain) bool {
+		for _, siteID := range domain.SiteIds {
+			if _, ok := f.sites[siteID]; !ok || f.owners[siteID] != org {
+				writeFakeError(w, http.StatusBadRequest, "site not found")
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case !hasID && r.Method == "POST":
+		var domain InternalDomain
+		if !decodeFakeBody(w, r, &domain) || !validSites(domain) {
+			return
+		}
+		domain.Id = f.newID(org)
+		domain.CreatedAt = fakeNow()
+		domain.ModifiedAt = domain.CreatedAt
+		f.internalDoms[domain.Id] = domain
+		writeFakeJSON(w, http.StatusOK, domain)
+	case hasID && r.Method == "GET":
+		writeFakeJSON(w, http.StatusOK, f.internalDoms[id])
+	case hasID && r.Method == "PUT":
+		var update InternalDomain
+		if !decodeFakeBody(w, r, &update) || !validSites(update) {
+			return
+		}
+		domain := f.internalDoms[id]
+		update.Id = domain.Id
+		update.CreatedAt = domain.CreatedAt
+		update.ModifiedAt = fakeNow()
+		f.internalDoms[id] = update
+		writeFakeJSON(w, http.StatusOK, update)
+	case hasID && r.Method == "DELETE":
+		delete(f.internalDoms, id)
+		delete(f.owners, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeUmbrella) serveTunnels(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool) {
+	switch {
+	case !hasID && r.Method == "GET":
+		tunnels := []umbrella.NetworkTunnel{}
+		for tunnelID, tunnel := range f.tunnels {
+			if f.owners[tunnelID] == org {
+				tunnels = append(tunnels, tunnel)
+			}
+		}
+		writeFakeJSON(w, http.StatusOK, tunnels)
+	case !hasID && r.Method == "POST":
+		var create umbrella.NetworkTunnelCreate
+		if !decodeFakeBody(w, r, &create) {
+			return
+		}
+		tunnel := umbrella.NetworkTunnel{
+			Id:           f.newID(org),
+			Name:         create.Name,
+			SiteOriginId: create.SiteOriginId,
+			Client: umbrella.TunnelClient{
+				DeviceType: fakeDefault(create.DeviceType, "other"),
+				Authentication: umbrella.TunnelAuth{
+					Type: fakeDefault(create.Authentication.Type, "PSK"),
+					Parameters: umbrella.TunnelAuthParams{
+						Id:         create.Authentication.Parameters.IdPrefix + "@" + strconv.FormatInt(org, 10) + "-fake.umbrella.com",
+						ModifiedAt: fakeNow(),
+					},
+				},
+			},
+			Transport:    umbrella.TunnelTrans{Protocol: fakeDefault(create.Transport.Protocol, "IPSec")},
+			ServiceType:  fakeDefault(create.ServiceType, "SIG"),
+			NetworkCIDRs: create.NetworkCIDRs,
+			CreatedAt:    fakeNow(),
+		}
+		tunnel.ModifiedAt = tunnel.CreatedAt
+		tunnel.Uri = "/deployments/v2/tunnels/" + strconv.FormatInt(tunnel.Id, 10)
+		f.tunnels[tunnel.Id] = tunnel
+		f.tunnelKeys[tunnel.Id] = []TunnelCredential{f.newTunnelKey(org, create.Authentication.Parameters.Secret)}
+
+		// Like the real API, the create response carries the collection URI
+		// and is the only response that echoes the secret.
+		created := tunnel
+		created.Uri = "/deployments/v2/tunnels"
+		created.Client.Authentication.Parameters.Secret = create.Authentication.Parameters.Secret
+		writeFakeJSON(w, http.StatusOK, created)
+	case hasID && r.Method == "GET":
+		tunnel, ok := f.tunnels[id]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "tunnel not found")
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, tunnel)
+	case hasID && r.Method == "PUT":
+		tunnel, ok := f.tunnels[id]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "tunnel not found")
+			return
+		}
+		var update umbrella.NetworkTunnel
+		if !decodeFakeBody(w, r, &update) {
+			return
+		}
+		tunnel.Name = fakeDefault(update.Name, tunnel.Name)
+		if update.SiteOriginId != 0 {
+			tunnel.SiteOriginId = update.SiteOriginId
+		}
+		tunnel.NetworkCIDRs = update.NetworkCIDRs
+		tunnel.ModifiedAt = fakeNow()
+		f.tunnels[id] = tunnel
+		writeFakeJSON(w, http.StatusOK, tunnel)
+	case hasID && r.Method == "DELETE":
+		if _, ok := f.tunnels[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "tunnel not found")
+			return
+		}
+		delete(f.tunnels, id)
+		delete(f.tunnelKeys, id)
+		delete(f.tunnelStates, id)
+		delete(f.owners, id)
+		writeFakeJSON(w, http.StatusOK, umbrella.Response{Message: "Tunnel deleted successfully"})
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// newTunnelKey returns a tunnel credential holding secret. The fake keeps the
+// secret so that tests can check which secrets a tunnel accepts.
+func (f *fakeUmbrella) newTunnelKey(org int64, secret string) TunnelCredential {
+	return TunnelCredential{
+		Id:        "key-" + strconv.FormatInt(f.newID(org), 10),
+		Secret:    secret,
+		CreatedAt: fakeNow(),
+	}
+}
+
+// tunnelSecrets returns the secrets the tunnel with id accepts.
+func (f *fakeUmbrella) tunnelSecrets(id string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tunnelID, _ := strconv.ParseInt(id, 10, 64)
+	var secrets []string
+	for _, key := range f.tunnelKeys[tunnelID] {
+		secrets = append(secrets, key.Secret)
+	}
+	return secrets
+}
+
+// setTunnelState sets the runtime state reported for the tunnel with id.
+func (f *fakeUmbrella) setTunnelState(id string, state TunnelState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tunnelID, _ := strconv.ParseInt(id, 10, 64)
+	state.TunnelId = tunnelID
+	f.tunnelStates[tunnelID] = state
+}
+
+func (f *fakeUmbrella) serveTunnelState(w http.ResponseWriter, r *http.Request, id int64) {
+	if _, ok := f.tunnels[id]; !ok {
+		writeFakeError(w, http.StatusNotFound, "tunnel not found")
+		return
+	}
+	if r.Method != "GET" {
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	state, ok := f.tunnelStates[id]
+	if !ok {
+		// A tunnel that never connected has no data center state.
+		state = TunnelState{TunnelId: id, Status: "DOWN", DataCenters: []TunnelDataCenterState{}}
+	}
+	writeFakeJSON(w, http.StatusOK, state)
+}
+
+func (f *fakeUmbrella) serveTunnelKeys(w http.ResponseWriter, r *http.Request, org, id int64, sub string) {
+	if _, ok := f.tunnels[id]; !ok {
+		writeFakeError(w, http.StatusNotFound, "tunnel not found")
+		return
+	}
+
+	keyID := strings.TrimPrefix(sub, "keys/")
+	switch {
+	case sub == "keys" && r.Method == "GET":
+		keys := []TunnelCredential{}
+		for _, key := range f.tunnelKeys[id] {
+			key.Secret = ""
+			keys = append(keys, key)
+		}
+		writeFakeJSON(w, http.StatusOK, keys)
+	case sub == "keys" && r.Method == "POST":
+		var create TunnelCredential
+		if !decodeFakeBody(w, r, &create) {
+			return
+		}
+		if err := validateTunnelSecret(create.Secret); err != nil {
+			writeFakeError(w, http.StatusBadRequest, "secret "+err.Error())
+			return
+		}
+		key := f.newTunnelKey(org, create.Secret)
+		f.tunnelKeys[id] = append(f.tunnelKeys[id], key)
+		key.Secret = ""
+		writeFakeJSON(w, http.StatusOK, key)
+	case keyID != sub && r.Method == "DELETE":
+		keys := f.tunnelKeys[id]
+		for i, key := range keys {
+			if key.Id == keyID {
+				f.tunnelKeys[id] = append(keys[:i:i], keys[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeFakeError(w, http.StatusNotFound, "key not found")
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeUmbrella) serveVAs(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool) {
+	switch {
+	case !hasID && r.Method == "GET":
+		pending := f.pendingVAs[:0]
+		for _, p := range f.pendingVAs {
+			if p.lists--; p.lists > 0 {
+				pending = append(pending, p)
+				continue
+			}
+			p.va.OriginId = f.newID(p.org)
+			p.va.CreatedAt = fakeNow()
+			p.va.ModifiedAt = p.va.CreatedAt
+			f.vas[p.va.OriginId] = p.va
+		}
+		f.pendingVAs = pending
+
+		vas := []umbrella.VA{}
+		for vaID, va := range f.vas {
+			if f.owners[vaID] == org {
+				vas = append(vas, va)
+			}
+		}
+		writeFakeJSON(w, http.StatusOK, vas)
+	case hasID && r.Method == "GET":
+		va, ok := f.vas[id]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "virtual appliance not found")
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, va)
+	case hasID && r.Method == "PUT":
+		va, ok := f.vas[id]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "virtual appliance not found")
+			return
+		}
+		var update umbrella.VA
+		if !decodeFakeBody(w, r, &update) {
+			return
+		}
+		if update.SiteId != 0 {
+			va.SiteId = update.SiteId
+		}
+		va.ModifiedAt = fakeNow()
+		f.vas[id] = va
+		writeFakeJSON(w, http.StatusOK, va)
+	case hasID && r.Method == "DELETE":
+		if _, ok := f.vas[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "virtual appliance not found")
+			return
+		}
+		delete(f.vas, id)
+		delete(f.owners, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeUmbrella) hasDestination(listID int64, value string) bool {
+	for _, destination := range f.destinations[listID] {
+		if destination.Destination == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeUmbrella) serveDestinationLists(w http.ResponseWriter, r *http.Request, org, id int64, hasID bool, sub string) {
+	if hasID {
+		if _, ok := f.destLists[id]; !ok {
+			writeFakeError(w, http.StatusNotFound, "destination list not found")
+			return
+		}
+	}
+
+	switch {
+	case !hasID && r.Method == "POST":
+		var list DestinationList
+		if !decodeFakeBody(w, r, &list) {
+			return
+		}
+		list.Id = f.newID(org)
+		if list.BundleTypeId == 0 {
+			list.BundleTypeId = 1
+		}
+		list.CreatedAt = time.Now().Unix()
+		list.ModifiedAt = list.CreatedAt
+		f.destLists[list.Id] = list
+		writeFakeJSON(w, http.StatusOK, destinationListResponse{Data: list})
+	case hasID && sub == "" && r.Method == "GET":
+		list := f.destLists[id]
+		list.Meta.DestinationCount = int64(len(f.destinations[id]))
+		writeFakeJSON(w, http.StatusOK, destinationListResponse{Data: list})
+	case hasID && sub == "" && r.Method == "PATCH":
+		var update DestinationList
+		if !decodeFakeBody(w, r, &update) {
+			return
+		}
+		list := f.destLists[id]
+		list.Name = update.Name
+		list.ModifiedAt = time.Now().Unix()
+		f.destLists[id] = list
+		writeFakeJSON(w, http.StatusOK, destinationListResponse{Data: list})
+	case hasID && sub == "" && r.Method == "DELETE":
+		delete(f.destLists, id)
+		delete(f.destinations, id)
+		delete(f.owners, id)
+		writeFakeJSON(w, http.StatusOK, map[string]interface{}{"status": map[string]interface{}{"code": 200, "text": "OK"}})
+	case hasID && sub == "destinations" && r.Method == "GET":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		destinations := f.destinations[id]
+		start, end := (page-1)*limit, page*limit
+		if start > len(destinations) {
+			start = len(destinations)
+		}
+		if end > len(destinations) {
+			end = len(destinations)
+		}
+		writeFakeJSON(w, http.StatusOK, destinationsResponse{Data: destinations[start:end]})
+	case hasID && sub == "destinations" && r.Method == "POST":
+		var add []Destination
+		if !decodeFakeBody(w, r, &add) {
+			return
+		}
+		if len(add) > destinationBatchSize {
+			writeFakeError(w, http.StatusBadRequest, "too many destinations")
+			return
+		}
+		for _, destination := range add {
+			if f.dedupDestinations && f.hasDestination(id, destination.Destination) {
+				continue
+			}
+			f.nextID++
+			destination.Id = strconv.FormatInt(f.nextID, 10)
+			destination.Type = "domain"
+			f.destinations[id] = append(f.destinations[id], destination)
+		}
+		f.destBatches++
+		writeFakeJSON(w, http.StatusOK, destinationListResponse{Data: f.destLists[id]})
+	case hasID && sub == "destinations/remove" && r.Method == "DELETE":
+		var remove []int64
+		if !decodeFakeBody(w, r, &remove) {
+			return
+		}
+		if len(remove) > destinationBatchSize {
+			writeFakeError(w, http.StatusBadRequest, "too many destinations")
+			return
+		}
+		removed := map[string]bool{}
+		for _, destinationID := range remove {
+			removed[strconv.FormatInt(destinationID, 10)] = true
+		}
+		kept := []Destination{}
+		for _, destination := range f.destinations[id] {
+			if !removed[destination.Id] {
+				kept = append(kept, destination)
+			}
+		}
+		f.destinations[id] = kept
+		f.destBatches++
+		writeFakeJSON(w, http.StatusOK, destinationListResponse{Data: f.destLists[id]})
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func fakeDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func decodeFakeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeFakeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// fakeRequestIDs numbers the request IDs of fake error responses.
+var fakeRequestIDs int64
+
+func writeFakeError(w http.ResponseWriter, status int, message string) {
+	writeFakeJSON(w, status, map[string]interface{}{
+		"statusCode": status,
+		"error":      http.StatusText(status),
+		"message":    message,
+		"txId":       fmt.Sprintf("fake-tx-%d", atomic.AddInt64(&fakeRequestIDs, 1)),
+	})
+}
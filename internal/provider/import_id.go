@@ -0,0 +1,117 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// importNamePrefix marks import IDs that select a resource by name.
+const importNamePrefix = "name:"
+
+// parseImportID parses import IDs of the form "<id>" or "<org_id>/<id>". The
+// returned organization ID is null when the import ID does not name one.
+func parseImportID(importID string) (types.Int64, int64, error) {
+	orgID, idPart, err := splitImportOrgID(importID)
+	if err != nil {
+		return orgID, 0, err
+	}
+
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil {
+		return orgID, 0, fmt.Errorf("invalid ID %q in import ID %q, expected <id> or <org_id>/<id>", idPart, importID)
+	}
+
+	return orgID, id, nil
+}
+
+// parseNamedImportID parses import IDs like parseImportID, additionally
+// accepting "name:<name>" and "<org_id>/name:<name>". Exactly one of the
+// returned ID and name is set.
+func parseNamedImportID(importID string) (types.Int64, int64, string, error) {
+	orgID, idPart, err := splitImportOrgID(importID)
+	if err != nil {
+		return orgID, 0, "", err
+	}
+
+	if strings.HasPrefix(idPart, importNamePrefix) {
+		name := strings.TrimPrefix(idPart, importNamePrefix)
+		if name == "" {
+			return orgID, 0, "", fmt.Errorf("empty name in import ID %q, expected name:<name>", importID)
+		}
+		return orgID, 0, name, nil
+	}
+
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil {
+		return orgID, 0, "", fmt.Errorf("invalid ID %q in import ID %q, expected <id>, name:<name>, <org_id>/<id> or <org_id>/name:<name>", idPart, importID)
+	}
+
+	return orgID, id, "", nil
+}
+
+// splitImportOrgID splits the "<org_id>/" prefix off importID. Names may
+// contain "/", so an import ID that starts with "name:" has no organization.
+func splitImportOrgID(importID string) (types.Int64, string, error) {
+	if strings.HasPrefix(importID, importNamePrefix) {
+		return types.Int64Null(), importID, nil
+	}
+
+	org, rest, found := strings.Cut(importID, "/")
+	if !found {
+		return types.Int64Null(), importID, nil
+	}
+
+	id, err := strconv.ParseInt(org, 10, 64)
+	if err != nil {
+		return types.Int64Null(), "", fmt.Errorf("invalid organization ID %q in import ID %q, expected <org_id>/<id>", org, importID)
+	}
+
+	return types.Int64Value(id), rest, nil
+}
+
+// importIDResolver lists the IDs of the resources named name in the
+// organization of api.
+type importIDResolver func(api *apiClient, name string) ([]int64, error)
+
+// resolveImportID parses importID with parseNamedImportID and looks names up
+// with resolve. kind names the resource type in diagnostics, e.g. "Site".
+func resolveImportID(client *apiClient, importID, kind string, resolve importIDResolver) (types.Int64, int64, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	summary := "Invalid Umbrella " + kind + " Import ID"
+
+	orgID, id, name, err := parseNamedImportID(importID)
+	if err != nil {
+		diags.AddError(summary, err.Error())
+		return orgID, 0, diags
+	}
+	if name == "" {
+		return orgID, id, diags
+	}
+
+	ids, err := resolve(client.forOrg(orgID), name)
+	if err != nil {
+		addAPIError(&diags, "Unable to Resolve Umbrella "+kind+" Import ID", fmt.Sprintf("Could not look up the Umbrella %s named %q", kind, name), err, nil)
+		return orgID, 0, diags
+	}
+
+	switch len(ids) {
+	case 0:
+		diags.AddError(summary, fmt.Sprintf("No Umbrella %s is named %q.", kind, name))
+	case 1:
+		id = ids[0]
+	default:
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		matches := make([]string, len(ids))
+		for i, id := range ids {
+			matches[i] = strconv.FormatInt(id, 10)
+		}
+		diags.AddError(summary, fmt.Sprintf("%d Umbrella %ss are named %q. Import one of them by ID instead: %s.", len(ids), kind, name, strings.Join(matches, ", ")))
+	}
+
+	return orgID, id, diags
+}
@@ -0,0 +1,78 @@
+package umbrellaprovider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseImportID(t *testing.T) {
+	cases := []struct {
+		importID string
+		orgID    types.Int64
+		id       int64
+		wantErr  bool
+	}{
+		{importID: "42", orgID: types.Int64Null(), id: 42},
+		{importID: "1234/42", orgID: types.Int64Value(1234), id: 42},
+		{importID: "", wantErr: true},
+		{importID: "4x2", wantErr: true},
+		{importID: "org/42", wantErr: true},
+		{importID: "1234/", wantErr: true},
+	}
+
+	for _, c := range cases {
+		orgID, id, err := parseImportID(c.importID)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", c.importID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.importID, err)
+			continue
+		}
+		if !orgID.Equal(c.orgID) || id != c.id {
+			t.Errorf("%q: got %s/%d, want %s/%d", c.importID, orgID, id, c.orgID, c.id)
+		}
+	}
+}
+
+func TestParseNamedImportID(t *testing.T) {
+	cases := []struct {
+		importID string
+		orgID    types.Int64
+		id       int64
+		name     string
+		wantErr  bool
+	}{
+		{importID: "42", orgID: types.Int64Null(), id: 42},
+		{importID: "1234/42", orgID: types.Int64Value(1234), id: 42},
+		{importID: "name:branch", orgID: types.Int64Null(), name: "branch"},
+		{importID: "name:HQ/Branch", orgID: types.Int64Null(), name: "HQ/Branch"},
+		{importID: "1234/name:HQ/Branch", orgID: types.Int64Value(1234), name: "HQ/Branch"},
+		{importID: "1234/name:branch 1", orgID: types.Int64Value(1234), name: "branch 1"},
+		{importID: "name:", wantErr: true},
+		{importID: "1234/name:", wantErr: true},
+		{importID: "Name:branch", wantErr: true},
+		{importID: "org/name:branch", wantErr: true},
+	}
+
+	for _, c := range cases {
+		orgID, id, name, err := parseNamedImportID(c.importID)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", c.importID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.importID, err)
+			continue
+		}
+		if !orgID.Equal(c.orgID) || id != c.id || name != c.name {
+			t.Errorf("%q: got %s/%d/%q, want %s/%d/%q", c.importID, orgID, id, name, c.orgID, c.id, c.name)
+		}
+	}
+}
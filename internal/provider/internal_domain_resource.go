@@ -0,0 +1,344 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &InternalDomainResource{}
+var _ resource.ResourceWithImportState = &InternalDomainResource{}
+var _ resource.ResourceWithValidateConfig = &InternalDomainResource{}
+
+func NewInternalDomainResource() resource.Resource {
+	return &InternalDomainResource{}
+}
+
+// InternalDomainResource defines the resource implementation.
+type InternalDomainResource struct {
+	client *apiClient
+}
+
+// InternalDomainResourceModel describes the resource data model.
+type InternalDomainResourceModel struct {
+	Id                      types.Int64  `tfsdk:"id"`
+	Domain                  types.String `tfsdk:"domain"`
+	Description             types.String `tfsdk:"description"`
+	IncludeAllVAs           types.Bool   `tfsdk:"include_all_vas"`
+	IncludeAllMobileDevices types.Bool   `tfsdk:"include_all_mobile_devices"`
+	SiteIds                 types.Set    `tfsdk:"site_ids"`
+	ModifiedAt              types.String `tfsdk:"modified_at"`
+	CreatedAt               types.String `tfsdk:"created_at"`
+	LastUpdated             types.String `tfsdk:"last_updated"`
+	OrgId                   types.Int64  `tfsdk:"org_id"`
+}
+
+func (r *InternalDomainResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_internal_domain"
+}
+
+func (r *InternalDomainResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Internal domain resource. Queries for internal domains are forwarded to local DNS by virtual appliances and roaming clients",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the internal domain",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				MarkdownDescription: "The internal domain name",
+				Required:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "The description of the internal domain",
+				Optional:            true,
+			},
+			"include_all_vas": schema.BoolAttribute{
+				MarkdownDescription: "Specifies whether all virtual appliances forward the domain. Conflicts with `site_ids`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"include_all_mobile_devices": schema.BoolAttribute{
+				MarkdownDescription: "Specifies whether all roaming clients and mobile devices forward the domain",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"site_ids": schema.SetAttribute{
+				MarkdownDescription: "The IDs of the Sites whose virtual appliances forward the domain",
+				Optional:            true,
+				ElementType:         types.Int64Type,
+			},
+			"modified_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the internal domain was modified",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the internal domain was created",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the internal domain. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+	}
+}
+
+func (r *InternalDomainResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data InternalDomainResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.IncludeAllVAs.ValueBool() && !data.SiteIds.IsNull() && len(data.SiteIds.Elements()) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("site_ids"),
+			"Conflicting Internal Domain Scope",
+			"site_ids cannot be set when include_all_vas is true, since the domain already applies to every virtual appliance",
+		)
+	}
+}
+
+func (r *InternalDomainResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *InternalDomainResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *InternalDomainResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId)
+
+	domainItem, diags := buildInternalDomainItem(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := api.CreateInternalDomain(domainItem, nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Creating Umbrella Internal Domain", "Could not create Umbrella Internal Domain", err, apiErrorPaths{http.StatusConflict: path.Root("domain")})
+		return
+	}
+
+	setInternalDomainModel(data, domain)
+	data.OrgId = api.orgIDValue()
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *InternalDomainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *InternalDomainResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId)
+
+	domain, err := api.GetInternalDomain(data.Id.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Internal Domain no longer exists, removing it from state", map[string]interface{}{
+			"id": data.Id.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Internal Domain", "Could not read Umbrella Internal Domain ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, nil)
+		return
+	}
+
+	setInternalDomainModel(data, domain)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *InternalDomainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *InternalDomainResourceModel
+	var statedata *InternalDomainResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &statedata)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(statedata.OrgId)
+
+	domainItem, diags := buildInternalDomainItem(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, err := api.UpdateInternalDomain(statedata.Id.ValueInt64(), domainItem, nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Updating Umbrella Internal Domain", "Could not update Umbrella Internal Domain ID "+strconv.FormatInt(statedata.Id.ValueInt64(), 10), err, apiErrorPaths{http.StatusConflict: path.Root("domain")})
+		return
+	}
+
+	setInternalDomainModel(data, domain)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *InternalDomainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *InternalDomainResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.forOrg(data.OrgId).DeleteInternalDomain(data.Id.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Internal Domain is already gone", map[string]interface{}{
+			"id": data.Id.ValueInt64(),
+		})
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Internal Domain", "Could not delete Umbrella Internal Domain ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, nil)
+		return
+	}
+}
+
+func (r *InternalDomainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	orgid, domainid, err := parseImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Umbrella Internal Domain Import ID",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), domainid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
+}
+
+func buildInternalDomainItem(ctx context.Context, data *InternalDomainResourceModel) (InternalDomain, diag.Diagnostics) {
+	domainItem := InternalDomain{
+		Domain:                  data.Domain.ValueString(),
+		Description:             data.Description.ValueString(),
+		IncludeAllVAs:           data.IncludeAllVAs.ValueBool(),
+		IncludeAllMobileDevices: data.IncludeAllMobileDevices.ValueBool(),
+		SiteIds:                 []int64{},
+	}
+
+	var diags diag.Diagnostics
+	if !data.SiteIds.IsNull() && !data.SiteIds.IsUnknown() {
+		diags = data.SiteIds.ElementsAs(ctx, &domainItem.SiteIds, false)
+	}
+
+	return domainItem, diags
+}
+
+func setInternalDomainModel(data *InternalDomainResourceModel, domain *InternalDomain) {
+	data.Id = types.Int64Value(domain.Id)
+	data.Domain = types.StringValue(domain.Domain)
+	data.Description = types.StringNull()
+	if domain.Description != "" {
+		data.Description = types.StringValue(domain.Description)
+	}
+	data.IncludeAllVAs = types.BoolValue(domain.IncludeAllVAs)
+	data.IncludeAllMobileDevices = types.BoolValue(domain.IncludeAllMobileDevices)
+
+	// An unset site_ids attribute stays unset while the domain has no Sites.
+	if len(domain.SiteIds) > 0 || !data.SiteIds.IsNull() {
+		siteids := make([]attr.Value, 0, len(domain.SiteIds))
+		for _, id := range domain.SiteIds {
+			siteids = append(siteids, types.Int64Value(id))
+		}
+		data.SiteIds = types.SetValueMust(types.Int64Type, siteids)
+	}
+
+	data.ModifiedAt = types.StringValue(domain.ModifiedAt)
+	data.CreatedAt = types.StringValue(domain.CreatedAt)
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+}
@@ -0,0 +1,101 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccInternalDomainResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var domainID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_site" "test" {
+  name = "branch"
+}
+
+resource "umbrella_internal_domain" "test" {
+  domain      = "corp.example.com"
+  description = "Active Directory"
+  site_ids    = [umbrella_site.test.site_id]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_internal_domain.test", "domain", "corp.example.com"),
+					resource.TestCheckResourceAttr("umbrella_internal_domain.test", "include_all_vas", "false"),
+					resource.TestCheckResourceAttr("umbrella_internal_domain.test", "include_all_mobile_devices", "false"),
+					resource.TestCheckResourceAttr("umbrella_internal_domain.test", "site_ids.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("umbrella_internal_domain.test", "site_ids.*", "umbrella_site.test", "site_id"),
+					testAccCaptureAttr("umbrella_internal_domain.test", "id", &domainID),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "umbrella_internal_domain.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_site" "test" {
+  name = "branch"
+}
+
+resource "umbrella_internal_domain" "test" {
+  domain                     = "corp.example.com"
+  include_all_vas            = true
+  include_all_mobile_devices = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_internal_domain.test", "include_all_vas", "true"),
+					resource.TestCheckResourceAttr("umbrella_internal_domain.test", "include_all_mobile_devices", "true"),
+					resource.TestCheckNoResourceAttr("umbrella_internal_domain.test", "description"),
+					resource.TestCheckNoResourceAttr("umbrella_internal_domain.test", "site_ids.#"),
+					testAccCheckAttrUnchanged("umbrella_internal_domain.test", "id", &domainID),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			if n := fake.internalDomainCount(); n != 0 {
+				return fmt.Errorf("%d internal domains left behind", n)
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccInternalDomainResource_conflictingScope(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_internal_domain" "test" {
+  domain          = "corp.example.com"
+  include_all_vas = true
+  site_ids        = [1]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Conflicting Internal Domain Scope`),
+			},
+		},
+	})
+}
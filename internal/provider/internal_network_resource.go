@@ -0,0 +1,379 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &InternalNetworkResource{}
+var _ resource.ResourceWithImportState = &InternalNetworkResource{}
+var _ resource.ResourceWithValidateConfig = &InternalNetworkResource{}
+
+func NewInternalNetworkResource() resource.Resource {
+	return &InternalNetworkResource{}
+}
+
+// InternalNetworkResource defines the resource implementation.
+type InternalNetworkResource struct {
+	client *apiClient
+}
+
+// InternalNetworkResourceModel describes the resource data model.
+type InternalNetworkResourceModel struct {
+	Id           types.Int64  `tfsdk:"id"`
+	OriginId     types.Int64  `tfsdk:"origin_id"`
+	Name         types.String `tfsdk:"name"`
+	IpAddress    types.String `tfsdk:"ip_address"`
+	PrefixLength types.Int64  `tfsdk:"prefix_length"`
+	SiteId       types.Int64  `tfsdk:"site_id"`
+	NetworkId    types.Int64  `tfsdk:"network_id"`
+	TunnelId     types.Int64  `tfsdk:"tunnel_id"`
+	SiteName     types.String `tfsdk:"site_name"`
+	NetworkName  types.String `tfsdk:"network_name"`
+	TunnelName   types.String `tfsdk:"tunnel_name"`
+	ModifiedAt   types.String `tfsdk:"modified_at"`
+	CreatedAt    types.String `tfsdk:"created_at"`
+	LastUpdated  types.String `tfsdk:"last_updated"`
+	OrgId        types.Int64  `tfsdk:"org_id"`
+}
+
+func (r *InternalNetworkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_internal_network"
+}
+
+func (r *InternalNetworkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Internal network resource. Exactly one of `site_id`, `network_id` or `tunnel_id` must be set",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"origin_id": schema.Int64Attribute{
+				MarkdownDescription: "The origin ID of the internal network",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the internal network",
+				Required:            true,
+			},
+			"ip_address": schema.StringAttribute{
+				MarkdownDescription: "The IPv4 network address of the internal network",
+				Required:            true,
+			},
+			"prefix_length": schema.Int64Attribute{
+				MarkdownDescription: "The length of the network prefix, from 8 to 32",
+				Required:            true,
+			},
+			"site_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the Site the internal network belongs to",
+				Optional:            true,
+			},
+			"network_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the network the internal network belongs to",
+				Optional:            true,
+			},
+			"tunnel_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the tunnel the internal network belongs to",
+				Optional:            true,
+			},
+			"site_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the Site the internal network belongs to",
+				Computed:            true,
+			},
+			"network_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the network the internal network belongs to",
+				Computed:            true,
+			},
+			"tunnel_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the tunnel the internal network belongs to",
+				Computed:            true,
+			},
+			"modified_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the internal network was modified",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the internal network was created",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the internal network. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+	}
+}
+
+func (r *InternalNetworkResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data InternalNetworkResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	associations := 0
+	for _, id := range []types.Int64{data.SiteId, data.NetworkId, data.TunnelId} {
+		if !id.IsNull() {
+			associations++
+		}
+	}
+	if associations != 1 {
+		resp.Diagnostics.AddError(
+			"Invalid Internal Network Association",
+			fmt.Sprintf("Exactly one of site_id, network_id or tunnel_id must be set, got %d", associations),
+		)
+	}
+
+	if data.IpAddress.IsUnknown() || data.PrefixLength.IsUnknown() {
+		return
+	}
+
+	if err := validateNetworkCIDR(data.IpAddress.ValueString(), data.PrefixLength.ValueInt64()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ip_address"),
+			"Invalid Internal Network CIDR",
+			err.Error(),
+		)
+	}
+}
+
+// validateNetworkCIDR checks that ipaddress/prefixlength is an IPv4 network
+// that Umbrella accepts, with ipaddress being the network address.
+func validateNetworkCIDR(ipaddress string, prefixlength int64) error {
+	ip := net.ParseIP(ipaddress).To4()
+	if ip == nil {
+		return fmt.Errorf("%q is not a valid IPv4 address", ipaddress)
+	}
+
+	if prefixlength < 8 || prefixlength > 32 {
+		return fmt.Errorf("prefix length must be between 8 and 32, got %d", prefixlength)
+	}
+
+	network := ip.Mask(net.CIDRMask(int(prefixlength), 32))
+	if !network.Equal(ip) {
+		return fmt.Errorf("%s/%d has host bits set, did you mean %s/%d?", ipaddress, prefixlength, network, prefixlength)
+	}
+
+	return nil
+}
+
+func (r *InternalNetworkResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *InternalNetworkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *InternalNetworkResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId)
+
+	network, err := api.CreateInternalNetwork(buildInternalNetworkItem(data), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Creating Umbrella Internal Network", "Could not create Umbrella Internal Network", err, apiErrorPaths{http.StatusConflict: path.Root("name")})
+		return
+	}
+
+	setInternalNetworkModel(data, network)
+	data.OrgId = api.orgIDValue()
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *InternalNetworkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *InternalNetworkResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId)
+
+	network, err := api.GetInternalNetwork(data.OriginId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Internal Network no longer exists, removing it from state", map[string]interface{}{
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Internal Network", "Could not read Umbrella Internal Network ID "+strconv.FormatInt(data.OriginId.ValueInt64(), 10), err, nil)
+		return
+	}
+
+	setInternalNetworkModel(data, network)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *InternalNetworkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *InternalNetworkResourceModel
+	var statedata *InternalNetworkResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &statedata)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(statedata.OrgId)
+
+	network, err := api.UpdateInternalNetwork(statedata.OriginId.ValueInt64(), buildInternalNetworkItem(data), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Updating Umbrella Internal Network", "Could not update Umbrella Internal Network ID "+strconv.FormatInt(statedata.OriginId.ValueInt64(), 10), err, apiErrorPaths{http.StatusConflict: path.Root("name")})
+		return
+	}
+
+	setInternalNetworkModel(data, network)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *InternalNetworkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *InternalNetworkResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.forOrg(data.OrgId).DeleteInternalNetwork(data.OriginId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Internal Network is already gone", map[string]interface{}{
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Internal Network", "Could not delete Umbrella Internal Network ID "+strconv.FormatInt(data.OriginId.ValueInt64(), 10), err, nil)
+		return
+	}
+}
+
+func (r *InternalNetworkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	orgid, originid, err := parseImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Umbrella Internal Network Import ID",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("origin_id"), originid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
+}
+
+func buildInternalNetworkItem(data *InternalNetworkResourceModel) InternalNetwork {
+	return InternalNetwork{
+		Name:         data.Name.ValueString(),
+		IpAddress:    data.IpAddress.ValueString(),
+		PrefixLength: data.PrefixLength.ValueInt64(),
+		SiteId:       data.SiteId.ValueInt64(),
+		NetworkId:    data.NetworkId.ValueInt64(),
+		TunnelId:     data.TunnelId.ValueInt64(),
+	}
+}
+
+func setInternalNetworkModel(data *InternalNetworkResourceModel, network *InternalNetwork) {
+	optionalID := func(id int64) types.Int64 {
+		if id == 0 {
+			return types.Int64Null()
+		}
+		return types.Int64Value(id)
+	}
+
+	data.Id = types.Int64Value(network.OriginId)
+	data.OriginId = types.Int64Value(network.OriginId)
+	data.Name = types.StringValue(network.Name)
+	data.IpAddress = types.StringValue(network.IpAddress)
+	data.PrefixLength = types.Int64Value(network.PrefixLength)
+	data.SiteId = optionalID(network.SiteId)
+	data.NetworkId = optionalID(network.NetworkId)
+	data.TunnelId = optionalID(network.TunnelId)
+	data.SiteName = types.StringValue(network.SiteName)
+	data.NetworkName = types.StringValue(network.NetworkName)
+	data.TunnelName = types.StringValue(network.TunnelName)
+	data.ModifiedAt = types.StringValue(network.ModifiedAt)
+	data.CreatedAt = types.StringValue(network.CreatedAt)
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+}
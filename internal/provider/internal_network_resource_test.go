@@ -0,0 +1,124 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccInternalNetworkResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var networkID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccInternalNetworkResourceConfig(fake, "office", "10.1.0.0", 16),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_internal_network.test", "name", "office"),
+					resource.TestCheckResourceAttr("umbrella_internal_network.test", "site_name", "branch"),
+					resource.TestCheckResourceAttrPair("umbrella_internal_network.test", "site_id", "umbrella_site.test", "site_id"),
+					resource.TestCheckResourceAttrSet("umbrella_internal_network.test", "origin_id"),
+					testAccCaptureAttr("umbrella_internal_network.test", "origin_id", &networkID),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "umbrella_internal_network.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateID("umbrella_internal_network.test", "origin_id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccInternalNetworkResourceConfig(fake, "office-lan", "10.1.2.0", 24),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_internal_network.test", "name", "office-lan"),
+					resource.TestCheckResourceAttr("umbrella_internal_network.test", "ip_address", "10.1.2.0"),
+					resource.TestCheckResourceAttr("umbrella_internal_network.test", "prefix_length", "24"),
+					testAccCheckAttrUnchanged("umbrella_internal_network.test", "origin_id", &networkID),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			if n := fake.internalNetworkCount(); n != 0 {
+				return fmt.Errorf("%d internal networks left behind", n)
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccInternalNetworkResource_invalidConfig(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccInternalNetworkResourceConfig(fake, "office", "10.1.2.3", 16),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`did you mean 10\.1\.0\.0/16`),
+			},
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_internal_network" "test" {
+  name          = "office"
+  ip_address    = "10.1.0.0"
+  prefix_length = 16
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Exactly one of site_id, network_id or tunnel_id`),
+			},
+		},
+	})
+}
+
+func TestValidateNetworkCIDR(t *testing.T) {
+	cases := []struct {
+		ip      string
+		prefix  int64
+		wantErr bool
+	}{
+		{ip: "10.0.0.0", prefix: 8},
+		{ip: "192.168.1.0", prefix: 24},
+		{ip: "192.168.1.1", prefix: 32},
+		{ip: "192.168.1.1", prefix: 24, wantErr: true},
+		{ip: "10.0.0.0", prefix: 7, wantErr: true},
+		{ip: "10.0.0.0", prefix: 33, wantErr: true},
+		{ip: "2001:db8::", prefix: 32, wantErr: true},
+		{ip: "not-an-ip", prefix: 24, wantErr: true},
+	}
+
+	for _, c := range cases {
+		err := validateNetworkCIDR(c.ip, c.prefix)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s/%d: got error %v, want error %t", c.ip, c.prefix, err, c.wantErr)
+		}
+	}
+}
+
+func testAccInternalNetworkResourceConfig(fake *fakeUmbrella, name, ip string, prefix int) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_site" "test" {
+  name = "branch"
+}
+
+resource "umbrella_internal_network" "test" {
+  name          = %[1]q
+  ip_address    = %[2]q
+  prefix_length = %[3]d
+  site_id       = umbrella_site.test.site_id
+}
+`, name, ip, prefix)
+}
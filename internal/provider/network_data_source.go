@@ -0,0 +1,211 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &NetworksDataSource{}
+var _ datasource.DataSourceWithConfigure = &NetworksDataSource{}
+
+func NewNetworksDataSource() datasource.DataSource {
+	return &NetworksDataSource{}
+}
+
+type NetworksDataSource struct {
+	client *apiClient
+}
+
+// NetworksDataSourceModel describes the data source data model.
+type NetworksDataSourceModel struct {
+	ID        types.String   `tfsdk:"id"`
+	Name      types.String   `tfsdk:"name"`
+	IpAddress types.String   `tfsdk:"ip_address"`
+	Status    types.String   `tfsdk:"status"`
+	IsDynamic types.Bool     `tfsdk:"is_dynamic"`
+	Networks  []NetworkModel `tfsdk:"networks"`
+}
+
+type NetworkModel struct {
+	OriginId     types.Int64  `tfsdk:"origin_id"`
+	Name         types.String `tfsdk:"name"`
+	IpAddress    types.String `tfsdk:"ip_address"`
+	PrefixLength types.Int64  `tfsdk:"prefix_length"`
+	IsDynamic    types.Bool   `tfsdk:"is_dynamic"`
+	IsVerified   types.Bool   `tfsdk:"is_verified"`
+	Status       types.String `tfsdk:"status"`
+	CreatedAt    types.String `tfsdk:"created_at"`
+}
+
+func (d *NetworksDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_networks"
+}
+
+func (d *NetworksDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Networks data source. Lists the egress networks registered in Umbrella, optionally filtered",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Placeholder identifier of the data source",
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only return the networks with this exact name",
+				Optional:            true,
+			},
+			"ip_address": schema.StringAttribute{
+				MarkdownDescription: "Only return the networks whose range contains this IPv4 address",
+				Optional:            true,
+				Validators: []validator.String{
+					stringCheck("value must be an IPv4 address", func(value string) error {
+						if net.ParseIP(value).To4() == nil {
+							return fmt.Errorf("%q is not a valid IPv4 address", value)
+						}
+						return nil
+					}),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return the networks with this status, one of " + quotedList(networkStatuses),
+				Optional:            true,
+				Validators: []validator.String{
+					stringOneOf(networkStatuses...),
+				},
+			},
+			"is_dynamic": schema.BoolAttribute{
+				MarkdownDescription: "Only return dynamic, or only static, networks",
+				Optional:            true,
+			},
+			"networks": schema.ListNestedAttribute{
+				MarkdownDescription: "The networks matching all filters",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"origin_id": schema.Int64Attribute{
+							MarkdownDescription: "The origin ID of the network",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the network",
+							Computed:            true,
+						},
+						"ip_address": schema.StringAttribute{
+							MarkdownDescription: "The IPv4 network address of the network",
+							Computed:            true,
+						},
+						"prefix_length": schema.Int64Attribute{
+							MarkdownDescription: "The length of the network prefix",
+							Computed:            true,
+						},
+						"is_dynamic": schema.BoolAttribute{
+							MarkdownDescription: "Whether the public IP address of the network changes",
+							Computed:            true,
+						},
+						"is_verified": schema.BoolAttribute{
+							MarkdownDescription: "Whether Umbrella has verified the ownership of the network",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "The status of the network",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time (ISO8601 timestamp) when the network was created",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *NetworksDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// matchNetwork reports whether network passes every filter set in the data source configuration.
+func matchNetwork(data NetworksDataSourceModel, network Network) bool {
+	if !data.Name.IsNull() && network.Name != data.Name.ValueString() {
+		return false
+	}
+	if !data.Status.IsNull() && network.Status != data.Status.ValueString() {
+		return false
+	}
+	if !data.IsDynamic.IsNull() && network.IsDynamic != data.IsDynamic.ValueBool() {
+		return false
+	}
+	if !data.IpAddress.IsNull() {
+		_, cidr, err := net.ParseCIDR(fmt.Sprintf("%s/%d", network.IpAddress, network.PrefixLength))
+		if err != nil || !cidr.Contains(net.ParseIP(data.IpAddress.ValueString())) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *NetworksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data NetworksDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	networks, err := d.client.GetNetworks(nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read Umbrella Networks", "Could not list Umbrella Networks", err, nil)
+		return
+	}
+
+	data.Networks = []NetworkModel{}
+	for _, network := range networks {
+		if !matchNetwork(data, network) {
+			continue
+		}
+		data.Networks = append(data.Networks, NetworkModel{
+			OriginId:     types.Int64Value(network.OriginId),
+			Name:         types.StringValue(network.Name),
+			IpAddress:    types.StringValue(network.IpAddress),
+			PrefixLength: types.Int64Value(network.PrefixLength),
+			IsDynamic:    types.BoolValue(network.IsDynamic),
+			IsVerified:   types.BoolValue(network.IsVerified),
+			Status:       types.StringValue(network.Status),
+			CreatedAt:    types.StringValue(network.CreatedAt),
+		})
+	}
+
+	data.ID = types.StringValue("networks")
+
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,62 @@
+package umbrellaprovider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccNetworksDataSource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_network" "hq" {
+  name          = "hq"
+  ip_address    = "198.51.100.0"
+  prefix_length = 29
+}
+
+resource "umbrella_network" "branch" {
+  name          = "branch"
+  ip_address    = "203.0.113.7"
+  prefix_length = 32
+  status        = "CLOSED"
+}
+
+data "umbrella_networks" "all" {
+  depends_on = [umbrella_network.hq, umbrella_network.branch]
+}
+
+data "umbrella_networks" "by_ip" {
+  ip_address = "198.51.100.5"
+  depends_on = [umbrella_network.hq, umbrella_network.branch]
+}
+
+data "umbrella_networks" "closed" {
+  status     = "CLOSED"
+  depends_on = [umbrella_network.hq, umbrella_network.branch]
+}
+
+data "umbrella_networks" "missing" {
+  name       = "nowhere"
+  depends_on = [umbrella_network.hq, umbrella_network.branch]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.umbrella_networks.all", "networks.#", "2"),
+					resource.TestCheckResourceAttr("data.umbrella_networks.by_ip", "networks.#", "1"),
+					resource.TestCheckResourceAttr("data.umbrella_networks.by_ip", "networks.0.name", "hq"),
+					resource.TestCheckResourceAttrPair("data.umbrella_networks.by_ip", "networks.0.origin_id", "umbrella_network.hq", "origin_id"),
+					resource.TestCheckResourceAttr("data.umbrella_networks.closed", "networks.#", "1"),
+					resource.TestCheckResourceAttr("data.umbrella_networks.closed", "networks.0.name", "branch"),
+					resource.TestCheckResourceAttr("data.umbrella_networks.missing", "networks.#", "0"),
+				),
+			},
+		},
+	})
+}
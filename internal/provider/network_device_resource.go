@@ -0,0 +1,342 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &NetworkDeviceResource{}
+var _ resource.ResourceWithImportState = &NetworkDeviceResource{}
+
+func NewNetworkDeviceResource() resource.Resource {
+	return &NetworkDeviceResource{}
+}
+
+// NetworkDeviceResource defines the resource implementation.
+type NetworkDeviceResource struct {
+	client *apiClient
+}
+
+// NetworkDeviceResourceModel describes the resource data model.
+type NetworkDeviceResourceModel struct {
+	Id           types.Int64  `tfsdk:"id"`
+	OriginId     types.Int64  `tfsdk:"origin_id"`
+	DeviceId     types.String `tfsdk:"device_id"`
+	Model        types.String `tfsdk:"model"`
+	MacAddress   types.String `tfsdk:"mac_address"`
+	Name         types.String `tfsdk:"name"`
+	SerialNumber types.String `tfsdk:"serial_number"`
+	Tag          types.String `tfsdk:"tag"`
+	CreatedAt    types.String `tfsdk:"created_at"`
+	LastUpdated  types.String `tfsdk:"last_updated"`
+	OrgId        types.Int64  `tfsdk:"org_id"`
+}
+
+func (r *NetworkDeviceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network_device"
+}
+
+func (r *NetworkDeviceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Network device resource. Registers a router, such as an ISR or a Meraki MX, that forwards DNS queries to Umbrella. Only the name can change without registering the device again",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"origin_id": schema.Int64Attribute{
+				MarkdownDescription: "The origin ID of the network device, used to assign it to policies",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"device_id": schema.StringAttribute{
+				MarkdownDescription: "The device ID Umbrella assigned to the network device",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"model": schema.StringAttribute{
+				MarkdownDescription: "The model of the network device",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"mac_address": schema.StringAttribute{
+				MarkdownDescription: "The MAC address of the network device",
+				Required:            true,
+				Validators: []validator.String{
+					stringCheck("value must be a 48-bit MAC address", func(value string) error {
+						if mac, err := net.ParseMAC(value); err != nil || len(mac) != 6 {
+							return fmt.Errorf("%q is not a 48-bit MAC address, such as 00:1a:2b:3c:4d:5e", value)
+						}
+						return nil
+					}),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the network device",
+				Required:            true,
+			},
+			"serial_number": schema.StringAttribute{
+				MarkdownDescription: "The serial number of the network device",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"tag": schema.StringAttribute{
+				MarkdownDescription: "A tag describing the network device",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the network device was registered",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the network device. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+	}
+}
+
+func (r *NetworkDeviceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *NetworkDeviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *NetworkDeviceResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	device, err := api.CreateNetworkDevice(NetworkDevice{
+		Model:        data.Model.ValueString(),
+		MacAddress:   data.MacAddress.ValueString(),
+		Name:         data.Name.ValueString(),
+		SerialNumber: data.SerialNumber.ValueString(),
+		Tag:          data.Tag.ValueString(),
+	}, nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Creating Umbrella Network Device", "Could not register Umbrella Network Device", err, apiErrorPaths{http.StatusConflict: path.Root("serial_number")})
+		return
+	}
+
+	setNetworkDeviceModel(data, device)
+	data.OrgId = api.orgIDValue()
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *NetworkDeviceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *NetworkDeviceResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	device, err := api.GetNetworkDevice(data.OriginId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Network Device no longer exists, removing it from state", map[string]interface{}{
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Network Device", "Could not read Umbrella Network Device ID "+strconv.FormatInt(data.OriginId.ValueInt64(), 10), err, nil)
+		return
+	}
+
+	setNetworkDeviceModel(data, device)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *NetworkDeviceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *NetworkDeviceResourceModel
+	var statedata *NetworkDeviceResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &statedata)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(statedata.OrgId).withContext(ctx)
+
+	// Every other attribute forces a new device, so only the name changes here.
+	device, err := api.RenameNetworkDevice(statedata.OriginId.ValueInt64(), data.Name.ValueString(), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Updating Umbrella Network Device", "Could not rename Umbrella Network Device ID "+strconv.FormatInt(statedata.OriginId.ValueInt64(), 10), err, apiErrorPaths{http.StatusBadRequest: path.Root("name")})
+		return
+	}
+
+	setNetworkDeviceModel(data, device)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *NetworkDeviceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *NetworkDeviceResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.forOrg(data.OrgId).withContext(ctx).DeleteNetworkDevice(data.OriginId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Network Device is already gone", map[string]interface{}{
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Network Device", "Could not deregister Umbrella Network Device ID "+strconv.FormatInt(data.OriginId.ValueInt64(), 10), err, nil)
+		return
+	}
+}
+
+func (r *NetworkDeviceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	orgid, originid, diags := resolveImportID(r.client, req.ID, "Network Device", func(api *apiClient, name string) ([]int64, error) {
+		devices, err := api.GetNetworkDevices(nil)
+		if err != nil {
+			return nil, err
+		}
+		var ids []int64
+		for _, device := range devices {
+			if device.Name == name {
+				ids = append(ids, device.OriginId)
+			}
+		}
+		return ids, nil
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("origin_id"), originid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
+}
+
+func setNetworkDeviceModel(data *NetworkDeviceResourceModel, device *NetworkDevice) {
+	data.Id = types.Int64Value(device.OriginId)
+	data.OriginId = types.Int64Value(device.OriginId)
+	data.DeviceId = types.StringValue(device.DeviceId)
+	data.Model = types.StringValue(device.Model)
+	data.Name = types.StringValue(device.Name)
+	data.SerialNumber = types.StringValue(device.SerialNumber)
+	data.Tag = types.StringNull()
+	if device.Tag != "" {
+		data.Tag = types.StringValue(device.Tag)
+	}
+	// Umbrella may spell the MAC address differently, so keep the configured
+	// spelling while it names the same address.
+	if !sameMACAddress(data.MacAddress.ValueString(), device.MacAddress) {
+		data.MacAddress = types.StringValue(device.MacAddress)
+	}
+	data.CreatedAt = types.StringValue(device.CreatedAt)
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+}
+
+// sameMACAddress reports whether a and b are the same hardware address,
+// whatever their case and separators.
+func sameMACAddress(a, b string) bool {
+	macA, err := net.ParseMAC(a)
+	if err != nil {
+		return false
+	}
+	macB, err := net.ParseMAC(b)
+	if err != nil {
+		return false
+	}
+	return macA.String() == macB.String()
+}
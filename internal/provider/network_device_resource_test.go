@@ -0,0 +1,118 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccNetworkDeviceResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var originID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccNetworkDeviceResourceConfig(fake, "branch-1-isr", "FGL2231A0BC"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_network_device.test", "name", "branch-1-isr"),
+					resource.TestCheckResourceAttr("umbrella_network_device.test", "model", "ISR4331"),
+					resource.TestCheckResourceAttr("umbrella_network_device.test", "mac_address", "00-1A-2B-3C-4D-5E"),
+					resource.TestCheckResourceAttr("umbrella_network_device.test", "tag", "branch"),
+					resource.TestCheckResourceAttrSet("umbrella_network_device.test", "device_id"),
+					resource.TestCheckResourceAttrSet("umbrella_network_device.test", "origin_id"),
+					testAccCaptureAttr("umbrella_network_device.test", "origin_id", &originID),
+				),
+			},
+			// ImportState testing. Umbrella returns the MAC address in
+			// canonical form rather than as configured.
+			{
+				ResourceName:            "umbrella_network_device.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateID("umbrella_network_device.test", "origin_id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "mac_address"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccNetworkDeviceResourceConfig(fake, "branch-1-router", "FGL2231A0BC"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_network_device.test", "name", "branch-1-router"),
+					testAccCheckAttrUnchanged("umbrella_network_device.test", "origin_id", &originID),
+				),
+			},
+			// A new serial number registers the device again
+			{
+				Config: testAccNetworkDeviceResourceConfig(fake, "branch-1-router", "FGL2231A0BD"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_network_device.test", "serial_number", "FGL2231A0BD"),
+					func(*terraform.State) error {
+						if n := fake.networkDeviceCount(); n != 1 {
+							return fmt.Errorf("expected 1 network device, got %d", n)
+						}
+						return nil
+					},
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			if n := fake.networkDeviceCount(); n != 0 {
+				return fmt.Errorf("%d network devices left behind", n)
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccNetworkDeviceResource_invalid(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_network_device" "test" {
+  model         = "MX68"
+  mac_address   = "00:1a:2b:3c:4d"
+  name          = "branch-2-mx"
+  serial_number = "Q2KY-ABCD-1234"
+}
+`,
+				ExpectError: regexp.MustCompile(`is not a 48-bit MAC address`),
+			},
+			{
+				Config: testAccNetworkDeviceResourceConfig(fake, "branch-1-isr", "FGL2231A0BC") + `
+resource "umbrella_network_device" "duplicate" {
+  depends_on    = [umbrella_network_device.test]
+  model         = "ISR4331"
+  mac_address   = "00:1a:2b:3c:4d:5f"
+  name          = "branch-1-isr-2"
+  serial_number = "FGL2231A0BC"
+}
+`,
+				ExpectError: regexp.MustCompile(`already exists`),
+			},
+		},
+	})
+}
+
+func testAccNetworkDeviceResourceConfig(fake *fakeUmbrella, name string, serial string) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_network_device" "test" {
+  model         = "ISR4331"
+  mac_address   = "00-1A-2B-3C-4D-5E"
+  name          = %q
+  serial_number = %q
+  tag           = "branch"
+}
+`, name, serial)
+}
@@ -0,0 +1,385 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &NetworkResource{}
+var _ resource.ResourceWithImportState = &NetworkResource{}
+var _ resource.ResourceWithValidateConfig = &NetworkResource{}
+
+// networkStatuses are the statuses Umbrella accepts for a network.
+var networkStatuses = []string{"OPEN", "CLOSED"}
+
+// Umbrella only registers egress networks from /29 down to single addresses.
+const (
+	networkMinPrefixLength = 29
+	networkMaxPrefixLength = 32
+)
+
+func NewNetworkResource() resource.Resource {
+	return &NetworkResource{}
+}
+
+// NetworkResource defines the resource implementation.
+type NetworkResource struct {
+	client *apiClient
+}
+
+// NetworkResourceModel describes the resource data model.
+type NetworkResourceModel struct {
+	Id           types.Int64  `tfsdk:"id"`
+	OriginId     types.Int64  `tfsdk:"origin_id"`
+	Name         types.String `tfsdk:"name"`
+	IpAddress    types.String `tfsdk:"ip_address"`
+	PrefixLength types.Int64  `tfsdk:"prefix_length"`
+	IsDynamic    types.Bool   `tfsdk:"is_dynamic"`
+	IsVerified   types.Bool   `tfsdk:"is_verified"`
+	Status       types.String `tfsdk:"status"`
+	CreatedAt    types.String `tfsdk:"created_at"`
+	LastUpdated  types.String `tfsdk:"last_updated"`
+	OrgId        types.Int64  `tfsdk:"org_id"`
+}
+
+func (r *NetworkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network"
+}
+
+func (r *NetworkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Network resource. Registers the public egress IP addresses of a location as an Umbrella network identity",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"origin_id": schema.Int64Attribute{
+				MarkdownDescription: "The origin ID of the network",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the network",
+				Required:            true,
+			},
+			"ip_address": schema.StringAttribute{
+				MarkdownDescription: "The public IPv4 network address of the network. Required unless `is_dynamic` is true, in which case Umbrella reports the current address",
+				Optional:            true,
+				Computed:            true,
+			},
+			"prefix_length": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The length of the network prefix, from %d to %d. Required unless `is_dynamic` is true", networkMinPrefixLength, networkMaxPrefixLength),
+				Optional:            true,
+				Computed:            true,
+			},
+			"is_dynamic": schema.BoolAttribute{
+				MarkdownDescription: "Whether the public IP address of the network changes, and is kept up to date by a dynamic IP updater",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"is_verified": schema.BoolAttribute{
+				MarkdownDescription: "Whether Umbrella has verified the ownership of the network",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The status of the network, one of " + quotedList(networkStatuses) + ". Defaults to `OPEN`",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringOneOf(networkStatuses...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the network was created",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the network. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+	}
+}
+
+func (r *NetworkResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data NetworkResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.IsDynamic.IsUnknown() || data.IpAddress.IsUnknown() || data.PrefixLength.IsUnknown() {
+		return
+	}
+
+	if data.IpAddress.IsNull() && data.PrefixLength.IsNull() && data.IsDynamic.ValueBool() {
+		return
+	}
+
+	if data.IpAddress.IsNull() || data.PrefixLength.IsNull() {
+		resp.Diagnostics.AddError(
+			"Missing Network Address",
+			"ip_address and prefix_length must both be set, unless is_dynamic is true and neither is set",
+		)
+		return
+	}
+
+	if err := validatePublicNetworkCIDR(data.IpAddress.ValueString(), data.PrefixLength.ValueInt64()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ip_address"),
+			"Invalid Network CIDR",
+			err.Error(),
+		)
+	}
+}
+
+// validatePublicNetworkCIDR checks that ipaddress/prefixlength is a public
+// IPv4 network that Umbrella registers as a network identity.
+func validatePublicNetworkCIDR(ipaddress string, prefixlength int64) error {
+	if prefixlength < networkMinPrefixLength || prefixlength > networkMaxPrefixLength {
+		return fmt.Errorf("prefix length must be between %d and %d, got %d", networkMinPrefixLength, networkMaxPrefixLength, prefixlength)
+	}
+
+	if err := validateNetworkCIDR(ipaddress, prefixlength); err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(ipaddress)
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%s is not a public IPv4 address, register internal addresses with umbrella_internal_network", ipaddress)
+	}
+
+	return nil
+}
+
+func (r *NetworkResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *NetworkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *NetworkResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId)
+
+	network, err := api.CreateNetwork(buildNetworkItem(data), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Creating Umbrella Network", "Could not register Umbrella Network", err, apiErrorPaths{http.StatusConflict: path.Root("name")})
+		return
+	}
+
+	setNetworkModel(data, network)
+	data.OrgId = api.orgIDValue()
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *NetworkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *NetworkResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId)
+
+	network, err := api.GetNetwork(data.OriginId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Network no longer exists, removing it from state", map[string]interface{}{
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Network", "Could not read Umbrella Network ID "+strconv.FormatInt(data.OriginId.ValueInt64(), 10), err, nil)
+		return
+	}
+
+	setNetworkModel(data, network)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *NetworkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *NetworkResourceModel
+	var statedata *NetworkResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &statedata)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(statedata.OrgId)
+
+	network, err := api.UpdateNetwork(statedata.OriginId.ValueInt64(), buildNetworkItem(data), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Updating Umbrella Network", "Could not update Umbrella Network ID "+strconv.FormatInt(statedata.OriginId.ValueInt64(), 10), err, apiErrorPaths{http.StatusConflict: path.Root("name")})
+		return
+	}
+
+	setNetworkModel(data, network)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *NetworkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *NetworkResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.forOrg(data.OrgId).DeleteNetwork(data.OriginId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Network is already gone", map[string]interface{}{
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Network", "Could not unregister Umbrella Network ID "+strconv.FormatInt(data.OriginId.ValueInt64(), 10), err, nil)
+		return
+	}
+}
+
+func (r *NetworkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	orgid, originid, diags := resolveImportID(r.client, req.ID, "Network", func(api *apiClient, name string) ([]int64, error) {
+		networks, err := api.GetNetworks(nil)
+		if err != nil {
+			return nil, err
+		}
+		var ids []int64
+		for _, network := range networks {
+			if network.Name == name {
+				ids = append(ids, network.OriginId)
+			}
+		}
+		return ids, nil
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("origin_id"), originid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
+}
+
+func buildNetworkItem(data *NetworkResourceModel) Network {
+	network := Network{
+		Name:         data.Name.ValueString(),
+		IpAddress:    data.IpAddress.ValueString(),
+		PrefixLength: data.PrefixLength.ValueInt64(),
+		IsDynamic:    data.IsDynamic.ValueBool(),
+	}
+	if !data.Status.IsUnknown() {
+		network.Status = data.Status.ValueString()
+	}
+	return network
+}
+
+func setNetworkModel(data *NetworkResourceModel, network *Network) {
+	data.Id = types.Int64Value(network.OriginId)
+	data.OriginId = types.Int64Value(network.OriginId)
+	data.Name = types.StringValue(network.Name)
+	data.IpAddress = types.StringNull()
+	data.PrefixLength = types.Int64Null()
+	if network.IpAddress != "" {
+		data.IpAddress = types.StringValue(network.IpAddress)
+		data.PrefixLength = types.Int64Value(network.PrefixLength)
+	}
+	data.IsDynamic = types.BoolValue(network.IsDynamic)
+	data.IsVerified = types.BoolValue(network.IsVerified)
+	data.Status = types.StringValue(network.Status)
+	data.CreatedAt = types.StringValue(network.CreatedAt)
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+}
@@ -0,0 +1,187 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccNetworkResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var networkID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccNetworkResourceConfig(fake, "branch-egress", "198.51.100.0", 29, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_network.test", "name", "branch-egress"),
+					resource.TestCheckResourceAttr("umbrella_network.test", "ip_address", "198.51.100.0"),
+					resource.TestCheckResourceAttr("umbrella_network.test", "prefix_length", "29"),
+					resource.TestCheckResourceAttr("umbrella_network.test", "is_dynamic", "false"),
+					resource.TestCheckResourceAttr("umbrella_network.test", "status", "OPEN"),
+					resource.TestCheckResourceAttrSet("umbrella_network.test", "origin_id"),
+					testAccCaptureAttr("umbrella_network.test", "origin_id", &networkID),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "umbrella_network.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateID("umbrella_network.test", "origin_id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			{
+				ResourceName:            "umbrella_network.test",
+				ImportState:             true,
+				ImportStateId:           "name:branch-egress",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccNetworkResourceConfig(fake, "branch-egress", "203.0.113.7", 32, "CLOSED"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_network.test", "ip_address", "203.0.113.7"),
+					resource.TestCheckResourceAttr("umbrella_network.test", "prefix_length", "32"),
+					resource.TestCheckResourceAttr("umbrella_network.test", "status", "CLOSED"),
+					testAccCheckAttrUnchanged("umbrella_network.test", "origin_id", &networkID),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			if n := fake.networkCount(); n != 0 {
+				return fmt.Errorf("%d networks left behind", n)
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccNetworkResource_dynamic(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var networkID string
+
+	config := fake.providerConfig() + `
+resource "umbrella_network" "test" {
+  name       = "home-office"
+  is_dynamic = true
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_network.test", "is_dynamic", "true"),
+					resource.TestCheckNoResourceAttr("umbrella_network.test", "ip_address"),
+					testAccCaptureAttr("umbrella_network.test", "origin_id", &networkID),
+				),
+			},
+			// The dynamic IP updater reports a new address, which is not drift.
+			{
+				PreConfig: func() { fake.setNetworkAddress(networkID, "198.51.100.23", 32) },
+				Config:    config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_network.test", "ip_address", "198.51.100.23"),
+					resource.TestCheckResourceAttr("umbrella_network.test", "prefix_length", "32"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNetworkResource_invalidConfig(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccNetworkResourceConfig(fake, "branch", "198.51.100.3", 29, ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`did you mean 198\.51\.100\.0/29`),
+			},
+			{
+				Config:      testAccNetworkResourceConfig(fake, "branch", "10.0.0.0", 29, ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`not a public IPv4 address`),
+			},
+			{
+				Config:      testAccNetworkResourceConfig(fake, "branch", "198.51.100.0", 24, ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`prefix length must be between 29 and 32`),
+			},
+			{
+				Config:      testAccNetworkResourceConfig(fake, "branch", "198.51.100.0", 29, "PAUSED"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`status must be one of "OPEN", "CLOSED"`),
+			},
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_network" "test" {
+  name = "branch"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`ip_address and prefix_length must both be set`),
+			},
+		},
+	})
+}
+
+func TestValidatePublicNetworkCIDR(t *testing.T) {
+	cases := []struct {
+		ip      string
+		prefix  int64
+		wantErr bool
+	}{
+		{ip: "198.51.100.0", prefix: 29},
+		{ip: "198.51.100.8", prefix: 30},
+		{ip: "203.0.113.7", prefix: 32},
+		{ip: "198.51.100.0", prefix: 28, wantErr: true},
+		{ip: "198.51.100.4", prefix: 29, wantErr: true},
+		{ip: "192.168.1.0", prefix: 29, wantErr: true},
+		{ip: "172.16.0.1", prefix: 32, wantErr: true},
+		{ip: "127.0.0.1", prefix: 32, wantErr: true},
+		{ip: "169.254.0.1", prefix: 32, wantErr: true},
+		{ip: "224.0.0.1", prefix: 32, wantErr: true},
+		{ip: "0.0.0.0", prefix: 32, wantErr: true},
+	}
+
+	for _, c := range cases {
+		err := validatePublicNetworkCIDR(c.ip, c.prefix)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s/%d: got error %v, want error %t", c.ip, c.prefix, err, c.wantErr)
+		}
+	}
+}
+
+// testAccNetworkResourceConfig configures a static network, with status
+// left to its default when empty.
+func testAccNetworkResourceConfig(fake *fakeUmbrella, name, ip string, prefix int, status string) string {
+	statusLine := ""
+	if status != "" {
+		statusLine = fmt.Sprintf("status        = %q", status)
+	}
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_network" "test" {
+  name          = %[1]q
+  ip_address    = %[2]q
+  prefix_length = %[3]d
+  %[4]s
+}
+`, name, ip, prefix, statusLine)
+}
@@ -0,0 +1,154 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PoliciesDataSource{}
+var _ datasource.DataSourceWithConfigure = &PoliciesDataSource{}
+
+func NewPoliciesDataSource() datasource.DataSource {
+	return &PoliciesDataSource{}
+}
+
+type PoliciesDataSource struct {
+	client *apiClient
+}
+
+// PoliciesDataSourceModel describes the data source data model.
+type PoliciesDataSourceModel struct {
+	ID       types.String  `tfsdk:"id"`
+	Type     types.String  `tfsdk:"type"`
+	Name     types.String  `tfsdk:"name"`
+	Policies []PolicyModel `tfsdk:"policies"`
+}
+
+type PolicyModel struct {
+	Id        types.Int64  `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	IsDefault types.Bool   `tfsdk:"is_default"`
+	Priority  types.Int64  `tfsdk:"priority"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+func (d *PoliciesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policies"
+}
+
+func (d *PoliciesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Policies data source. Lists the DNS or web policies of the organization, optionally filtered by name",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Placeholder identifier of the data source",
+				Computed:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of policy to list, one of " + quotedList(policyTypes),
+				Required:            true,
+				Validators: []validator.String{
+					stringOneOf(policyTypes...),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only return the policies with this exact name",
+				Optional:            true,
+			},
+			"policies": schema.ListNestedAttribute{
+				MarkdownDescription: "The policies matching all filters, in the order Umbrella lists them",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							MarkdownDescription: "The ID of the policy",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the policy",
+							Computed:            true,
+						},
+						"is_default": schema.BoolAttribute{
+							MarkdownDescription: "Whether the policy is the default policy, which applies to identities without another policy",
+							Computed:            true,
+						},
+						"priority": schema.Int64Attribute{
+							MarkdownDescription: "The priority of the policy",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time (ISO8601 timestamp) when the policy was created",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *PoliciesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *PoliciesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data PoliciesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policies, err := d.client.GetPolicies(data.Type.ValueString(), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read Umbrella Policies", "Could not list Umbrella "+data.Type.ValueString()+" Policies", err, nil)
+		return
+	}
+
+	data.Policies = []PolicyModel{}
+	for _, policy := range policies {
+		if !data.Name.IsNull() && policy.Name != data.Name.ValueString() {
+			continue
+		}
+		data.Policies = append(data.Policies, PolicyModel{
+			Id:        types.Int64Value(policy.Id),
+			Name:      types.StringValue(policy.Name),
+			IsDefault: types.BoolValue(policy.IsDefault),
+			Priority:  types.Int64Value(policy.Priority),
+			CreatedAt: types.StringValue(policy.CreatedAt),
+		})
+	}
+
+	data.ID = types.StringValue(data.Type.ValueString() + "_policies")
+
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
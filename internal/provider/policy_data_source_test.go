@@ -0,0 +1,54 @@
+package umbrellaprovider
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPoliciesDataSource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.addPolicy(0, "dns", "Default Policy")
+	branches := fake.addPolicy(0, "dns", "Branches")
+	fake.addPolicy(0, "web", "Branches")
+	fake.addPolicy(4321, "dns", "Child Policy")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+data "umbrella_policies" "swg" {
+  type = "swg"
+}
+`,
+				ExpectError: regexp.MustCompile(`type must be one of "dns", "web"`),
+			},
+			{
+				Config: fake.providerConfig() + `
+data "umbrella_policies" "dns" {
+  type = "dns"
+}
+
+data "umbrella_policies" "branches" {
+  type = "dns"
+  name = "Branches"
+}
+
+data "umbrella_policies" "web" {
+  type = "web"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.umbrella_policies.dns", "policies.#", "2"),
+					resource.TestCheckResourceAttr("data.umbrella_policies.branches", "policies.#", "1"),
+					resource.TestCheckResourceAttr("data.umbrella_policies.branches", "policies.0.id", strconv.FormatInt(branches.Id, 10)),
+					resource.TestCheckResourceAttr("data.umbrella_policies.web", "policies.#", "1"),
+				),
+			},
+		},
+	})
+}
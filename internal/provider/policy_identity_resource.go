@@ -0,0 +1,258 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PolicyIdentityResource{}
+var _ resource.ResourceWithImportState = &PolicyIdentityResource{}
+
+func NewPolicyIdentityResource() resource.Resource {
+	return &PolicyIdentityResource{}
+}
+
+// PolicyIdentityResource defines the resource implementation.
+type PolicyIdentityResource struct {
+	client *apiClient
+}
+
+// PolicyIdentityResourceModel describes the resource data model.
+type PolicyIdentityResourceModel struct {
+	Id          types.String `tfsdk:"id"`
+	PolicyId    types.Int64  `tfsdk:"policy_id"`
+	OriginId    types.Int64  `tfsdk:"origin_id"`
+	LastUpdated types.String `tfsdk:"last_updated"`
+	OrgId       types.Int64  `tfsdk:"org_id"`
+}
+
+func (r *PolicyIdentityResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policy_identity"
+}
+
+func (r *PolicyIdentityResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Policy identity resource. Attaches an identity, such as a site, network, virtual appliance, tunnel or network device, to a DNS or web policy by its origin ID. " +
+			"Umbrella does not list the identities of a policy, so an identity detached outside Terraform is not detected",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The policy ID and origin ID, as `<policy_id>:<origin_id>`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"policy_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the policy",
+				Required:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"origin_id": schema.Int64Attribute{
+				MarkdownDescription: "The origin ID of the identity to attach, such as the `origin_id` of an `umbrella_site` or `umbrella_va`",
+				Required:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the policy. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+	}
+}
+
+func (r *PolicyIdentityResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *PolicyIdentityResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *PolicyIdentityResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+	policyID, originID := data.PolicyId.ValueInt64(), data.OriginId.ValueInt64()
+
+	err := api.AddPolicyIdentity(policyID, originID, nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Attaching Umbrella Policy Identity", fmt.Sprintf("Could not attach origin ID %d to Umbrella Policy ID %d", originID, policyID), err, apiErrorPaths{
+			http.StatusNotFound:   path.Root("policy_id"),
+			http.StatusBadRequest: path.Root("origin_id"),
+		})
+		return
+	}
+
+	data.Id = types.StringValue(policyIdentityID(policyID, originID))
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	data.OrgId = api.orgIDValue()
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyIdentityResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *PolicyIdentityResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+	policyID := data.PolicyId.ValueInt64()
+
+	// Only the policy itself can be checked, as its identities are not listed.
+	found := false
+	for _, policyType := range policyTypes {
+		policies, err := api.GetPolicies(policyType, nil)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Error Reading Umbrella Policy Identity", "Could not list Umbrella "+policyType+" Policies", err, nil)
+			return
+		}
+		for _, policy := range policies {
+			if policy.Id == policyID {
+				found = true
+			}
+		}
+	}
+	if !found {
+		tflog.Warn(ctx, "Umbrella Policy no longer exists, removing its identity from state", map[string]interface{}{
+			"policy_id": policyID,
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.Id = types.StringValue(policyIdentityID(policyID, data.OriginId.ValueInt64()))
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only stores the plan, as every attribute that can change replaces
+// the attachment.
+func (r *PolicyIdentityResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *PolicyIdentityResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PolicyIdentityResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *PolicyIdentityResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policyID, originID := data.PolicyId.ValueInt64(), data.OriginId.ValueInt64()
+
+	err := r.client.forOrg(data.OrgId).withContext(ctx).RemovePolicyIdentity(policyID, originID, nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Policy Identity is already detached", map[string]interface{}{
+			"policy_id": policyID,
+			"origin_id": originID,
+		})
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Detaching Umbrella Policy Identity", fmt.Sprintf("Could not detach origin ID %d from Umbrella Policy ID %d", originID, policyID), err, nil)
+		return
+	}
+}
+
+func (r *PolicyIdentityResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	orgid, policyID, originID, err := parsePolicyIdentityImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Umbrella Policy Identity Import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("policy_id"), policyID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("origin_id"), originID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
+}
+
+func policyIdentityID(policyID, originID int64) string {
+	return strconv.FormatInt(policyID, 10) + ":" + strconv.FormatInt(originID, 10)
+}
+
+// parsePolicyIdentityImportID parses import IDs of the form
+// "<policy_id>:<origin_id>" or "<org_id>/<policy_id>:<origin_id>".
+func parsePolicyIdentityImportID(importID string) (types.Int64, int64, int64, error) {
+	orgID, rest, err := splitImportOrgID(importID)
+	if err != nil {
+		return orgID, 0, 0, err
+	}
+
+	policyPart, originPart, found := strings.Cut(rest, ":")
+	policyID, policyErr := strconv.ParseInt(policyPart, 10, 64)
+	originID, originErr := strconv.ParseInt(originPart, 10, 64)
+	if !found || policyErr != nil || originErr != nil {
+		return orgID, 0, 0, fmt.Errorf("invalid import ID %q, expected <policy_id>:<origin_id> or <org_id>/<policy_id>:<origin_id>", importID)
+	}
+
+	return orgID, policyID, originID, nil
+}
@@ -0,0 +1,134 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPolicyIdentityResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	branches := fake.addPolicy(0, "dns", "Branches")
+	strict := fake.addPolicy(0, "dns", "Strict")
+	var originID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccPolicyIdentityResourceConfig(fake, "Branches"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_policy_identity.test", "policy_id", strconv.FormatInt(branches.Id, 10)),
+					resource.TestCheckResourceAttrPair("umbrella_policy_identity.test", "origin_id", "umbrella_site.test", "origin_id"),
+					testAccCaptureAttr("umbrella_site.test", "origin_id", &originID),
+					testAccCheckAttached(fake, branches.Id, &originID, true),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "umbrella_policy_identity.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Moving the identity to another policy
+			{
+				Config: testAccPolicyIdentityResourceConfig(fake, "Strict"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_policy_identity.test", "policy_id", strconv.FormatInt(strict.Id, 10)),
+					testAccCheckAttached(fake, branches.Id, &originID, false),
+					testAccCheckAttached(fake, strict.Id, &originID, true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPolicyIdentityResource_policyDeleted(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	policy := fake.addPolicy(0, "web", "Branches")
+	config := fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_site" "test" {
+  name = "branch-1"
+}
+
+resource "umbrella_policy_identity" "test" {
+  policy_id = %d
+  origin_id = umbrella_site.test.origin_id
+}
+`, policy.Id)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig:          func() { fake.remove(strconv.FormatInt(policy.Id, 10)) },
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: func(s *terraform.State) error {
+					if _, ok := s.RootModule().Resources["umbrella_policy_identity.test"]; ok {
+						return fmt.Errorf("umbrella_policy_identity.test is still in state")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestParsePolicyIdentityImportID(t *testing.T) {
+	orgID, policyID, originID, err := parsePolicyIdentityImportID("1234/10:20")
+	if err != nil || orgID.ValueInt64() != 1234 || policyID != 10 || originID != 20 {
+		t.Errorf("parsePolicyIdentityImportID(1234/10:20) = %v, %d, %d, %v", orgID, policyID, originID, err)
+	}
+
+	orgID, policyID, originID, err = parsePolicyIdentityImportID("10:20")
+	if err != nil || !orgID.IsNull() || policyID != 10 || originID != 20 {
+		t.Errorf("parsePolicyIdentityImportID(10:20) = %v, %d, %d, %v", orgID, policyID, originID, err)
+	}
+
+	for _, importID := range []string{"10", "10:", ":20", "10:x", "x/10:20"} {
+		if _, _, _, err := parsePolicyIdentityImportID(importID); err == nil {
+			t.Errorf("parsePolicyIdentityImportID(%q) succeeded", importID)
+		}
+	}
+}
+
+// testAccCheckAttached checks whether the identity with *originID is
+// attached to the policy with policyID.
+func testAccCheckAttached(fake *fakeUmbrella, policyID int64, originID *string, attached bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		id, _ := strconv.ParseInt(*originID, 10, 64)
+		if fake.isAttached(policyID, id) != attached {
+			return fmt.Errorf("expected attached to policy %d to be %t", policyID, attached)
+		}
+		return nil
+	}
+}
+
+func testAccPolicyIdentityResourceConfig(fake *fakeUmbrella, policy string) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_site" "test" {
+  name = "branch-1"
+}
+
+data "umbrella_policies" "test" {
+  type = "dns"
+  name = %q
+}
+
+resource "umbrella_policy_identity" "test" {
+  policy_id = data.umbrella_policies.test.policies[0].id
+  origin_id = umbrella_site.test.origin_id
+}
+`, policy)
+}
@@ -3,6 +3,8 @@ package umbrellaprovider
 import (
 	"context"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -11,8 +13,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-
-	"github.com/olegunza/umbrella-api-go/umbrella"
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
@@ -32,6 +32,10 @@ type UmbrellaProviderModel struct {
 	Host      types.String `tfsdk:"host"`
 	Apikey    types.String `tfsdk:"apikey"`
 	Apisecret types.String `tfsdk:"apisecret"`
+	OrgId     types.Int64  `tfsdk:"org_id"`
+
+	MaxRetries   types.Int64 `tfsdk:"max_retries"`
+	RetryMaxWait types.Int64 `tfsdk:"retry_max_wait"`
 }
 
 func (p *umbrellaProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -55,6 +59,19 @@ func (p *umbrellaProvider) Schema(ctx context.Context, req provider.SchemaReques
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "ID of the child organization to manage when the API key belongs to a managed provider console. " +
+					"Resources can override it with their own `org_id`",
+				Optional: true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Number of times a request answered with 429, 502, 503 or 504 is retried. Defaults to 4, 0 disables retries",
+				Optional:            true,
+			},
+			"retry_max_wait": schema.Int64Attribute{
+				MarkdownDescription: "Longest wait in seconds before a single retry. Requests whose `Retry-After` exceeds it are not retried. Defaults to 30",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -96,6 +113,15 @@ func (p *umbrellaProvider) Configure(ctx context.Context, req provider.Configure
 		)
 	}
 
+	if config.OrgId.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("org_id"),
+			"Unknown Umbrella Organization ID",
+			"The provider cannot create the Umbrella API client as there is an unknown configuration value for the Umbrella organization ID. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the UMBRELLA_ORG_ID environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -104,6 +130,20 @@ func (p *umbrellaProvider) Configure(ctx context.Context, req provider.Configure
 	apikey := os.Getenv("UMBRELLA_APIKEY")
 	apisecret := os.Getenv("UMBRELLA_APISECRET")
 
+	var orgid int64
+	if v := os.Getenv("UMBRELLA_ORG_ID"); v != "" {
+		var err error
+		orgid, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("org_id"),
+				"Invalid Umbrella Organization ID",
+				"The UMBRELLA_ORG_ID environment variable must be a numeric organization ID, got: "+v,
+			)
+			return
+		}
+	}
+
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
 	}
@@ -116,6 +156,10 @@ func (p *umbrellaProvider) Configure(ctx context.Context, req provider.Configure
 		apisecret = config.Apisecret.ValueString()
 	}
 
+	if !config.OrgId.IsNull() {
+		orgid = config.OrgId.ValueInt64()
+	}
+
 	if host == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("host"),
@@ -146,11 +190,35 @@ func (p *umbrellaProvider) Configure(ctx context.Context, req provider.Configure
 		)
 	}
 
+	retries := defaultRetryPolicy()
+
+	if !config.MaxRetries.IsNull() && !config.MaxRetries.IsUnknown() {
+		if config.MaxRetries.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retries"),
+				"Invalid Umbrella Max Retries",
+				"max_retries must not be negative, got: "+strconv.FormatInt(config.MaxRetries.ValueInt64(), 10),
+			)
+		}
+		retries.maxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	if !config.RetryMaxWait.IsNull() && !config.RetryMaxWait.IsUnknown() {
+		if config.RetryMaxWait.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("retry_max_wait"),
+				"Invalid Umbrella Retry Max Wait",
+				"retry_max_wait must be at least 1 second, got: "+strconv.FormatInt(config.RetryMaxWait.ValueInt64(), 10),
+			)
+		}
+		retries.maxWait = time.Duration(config.RetryMaxWait.ValueInt64()) * time.Second
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client, err := umbrella.NewClient(&host, &apikey, &apisecret)
+	client, err := newAPIClient(ctx, host, apikey, apisecret, orgid, retries)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Umbrella API Client",
@@ -168,19 +236,33 @@ func (p *umbrellaProvider) Configure(ctx context.Context, req provider.Configure
 
 func (p *umbrellaProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
-		NewExampleResource,
 		NewSiteResource,
 		NewVAResource,
 		NewTunnelResource,
+		NewDestinationListResource,
+		NewInternalNetworkResource,
+		NewInternalDomainResource,
+		NewTunnelCredentialsResource,
+		NewNetworkResource,
+		NewNetworkDeviceResource,
+		NewRoamingComputerResource,
+		NewTagResource,
+		NewTagDevicesResource,
+		NewPolicyIdentityResource,
+		NewSWGDeviceSettingsResource,
 	}
 }
 
 func (p *umbrellaProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
-		NewExampleDataSource,
 		NewSiteDataSource,
 		NewVADataSource,
 		NewDClistDataSource,
+		NewTunnelDataSource,
+		NewTunnelStateDataSource,
+		NewNetworksDataSource,
+		NewRoamingComputersDataSource,
+		NewPoliciesDataSource,
 	}
 }
 
@@ -1,10 +1,13 @@
 package umbrellaprovider
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -12,7 +15,7 @@ import (
 // CLI command executed to create a provider server to which the CLI can
 // reattach.
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"scaffolding": providerserver.NewProtocol6WithError(New("test")()),
+	"umbrella": providerserver.NewProtocol6WithError(New("test")()),
 }
 
 func testAccPreCheck(t *testing.T) {
@@ -20,3 +23,36 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+// testAccImportStateID returns an ImportStateIdFunc that imports resourceName
+// by the value of its attribute in state.
+func testAccImportStateID(resourceName, attribute string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", resourceName)
+		}
+		return rs.Primary.Attributes[attribute], nil
+	}
+}
+
+// testAccCaptureAttr stores the value of attribute of resourceName in value.
+func testAccCaptureAttr(resourceName, attribute string, value *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+		*value = rs.Primary.Attributes[attribute]
+		return nil
+	}
+}
+
+// testAccCheckAttrUnchanged fails when attribute of resourceName no longer
+// holds the value captured by testAccCaptureAttr, i.e. when the resource was
+// replaced instead of updated in place.
+func testAccCheckAttrUnchanged(resourceName, attribute string, value *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		return resource.TestCheckResourceAttr(resourceName, attribute, *value)(s)
+	}
+}
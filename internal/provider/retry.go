@@ -0,0 +1,201 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultMaxRetries   = 4
+	defaultRetryMaxWait = 30 * time.Second
+	retryBaseWait       = time.Second
+)
+
+// retryPolicy controls how throttled and unavailable responses are retried.
+type retryPolicy struct {
+	// maxRetries is the number of retries after the first attempt.
+	maxRetries int
+	// maxWait caps the wait before a single retry. A Retry-After longer than
+	// maxWait is not honoured by retrying early; the response is returned.
+	maxWait time.Duration
+	// baseWait is the wait before the first retry when the server does not
+	// send Retry-After. It doubles with every further retry.
+	baseWait time.Duration
+}
+
+func defaultRetryPolicy() retryPolicy {
+	return retryPolicy{
+		maxRetries: defaultMaxRetries,
+		maxWait:    defaultRetryMaxWait,
+		baseWait:   retryBaseWait,
+	}
+}
+
+// retryableStatus reports whether a response with status to a request with
+// method is worth retrying. A 502 or 504 may come after the request was
+// processed, so only idempotent requests are retried then; retrying a POST
+// could create the object twice.
+func retryableStatus(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		switch method {
+		case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+			return true
+		}
+	}
+	return false
+}
+
+// retryTransport retries requests answered with 429 or 503, and idempotent
+// requests answered with 502 or 504. It also applies requestTimeout to every
+// attempt rather than to the whole exchange, so that waiting between retries
+// does not eat into the request timeout.
+type retryTransport struct {
+	policy retryPolicy
+	base   http.RoundTripper
+	// ctx carries the provider logger for requests that are issued without a
+	// context of their own.
+	ctx context.Context
+}
+
+// logContext returns the context to log retries of req with: the context of
+// the request, so that messages carry the logger fields of the calling
+// resource, or the provider context when the request has none.
+func (t *retryTransport) logContext(req *http.Request) context.Context {
+	if req.Context() == context.Background() {
+		return t.ctx
+	}
+	return req.Context()
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := t.attempt(req, attempt)
+		if err != nil || !retryableStatus(req.Method, res.StatusCode) || attempt >= t.policy.maxRetries {
+			return res, err
+		}
+
+		// The body of the previous attempt is consumed, so only requests
+		// that can rewind it are retried.
+		if req.Body != nil && req.GetBody == nil {
+			return res, nil
+		}
+
+		wait, ok := t.wait(res, attempt)
+		if !ok {
+			tflog.Warn(t.logContext(req), "Umbrella API asked to retry later than retry_max_wait allows, giving up", map[string]interface{}{
+				"method":      req.Method,
+				"url":         req.URL.String(),
+				"status":      res.StatusCode,
+				"retry_after": res.Header.Get("Retry-After"),
+			})
+			return res, nil
+		}
+
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+
+		tflog.Warn(t.logContext(req), "Retrying Umbrella API request", map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"status":  res.StatusCode,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends req once, bounded by requestTimeout. The timeout stays in
+// force until the response body is closed.
+func (t *retryTransport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), requestTimeout)
+
+	out := req.Clone(ctx)
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		out.Body = body
+	}
+
+	res, err := t.base.RoundTrip(out)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// wait returns how long to wait before retrying after res, and false when the
+// server asks for a longer wait than the policy allows.
+func (t *retryTransport) wait(res *http.Response, attempt int) (time.Duration, bool) {
+	if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+		return retryAfter, retryAfter <= t.policy.maxWait
+	}
+
+	backoff := t.policy.baseWait << uint(attempt)
+	if backoff <= 0 || backoff > t.policy.maxWait {
+		backoff = t.policy.maxWait
+	}
+
+	// Equal jitter keeps at least half of the backoff while spreading
+	// concurrent retries apart.
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1)), true
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP
+// date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,196 @@
+package umbrellaprovider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+// testRetryServer answers the first failures requests with status and the
+// given Retry-After header, and echoes the request body afterwards.
+func testRetryServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *int32) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func testRetryClient(maxRetries int, maxWait time.Duration) *http.Client {
+	return &http.Client{Transport: &retryTransport{
+		policy: retryPolicy{maxRetries: maxRetries, maxWait: maxWait, baseWait: time.Millisecond},
+		base:   http.DefaultTransport,
+		ctx:    context.Background(),
+	}}
+}
+
+func TestRetryTransportRetriesRetryableStatus(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		server, calls := testRetryServer(t, 2, status, "")
+
+		req, _ := http.NewRequest("PUT", server.URL, strings.NewReader(`{"name":"one"}`))
+		res, err := testRetryClient(4, time.Second).Do(req)
+		if err != nil {
+			t.Fatalf("%d: %s", status, err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusOK || string(body) != `{"name":"one"}` {
+			t.Errorf("%d: expected replayed body with 200, got %d %q", status, res.StatusCode, body)
+		}
+		if *calls != 3 {
+			t.Errorf("%d: expected 3 attempts, got %d", status, *calls)
+		}
+	}
+}
+
+func TestRetryTransportLogsWithRequestContext(t *testing.T) {
+	server, _ := testRetryServer(t, 1, http.StatusServiceUnavailable, "")
+
+	var output bytes.Buffer
+	ctx := tflog.SetField(tflogtest.RootLogger(context.Background(), &output), "resource", "umbrella_site")
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	res, err := testRetryClient(4, time.Second).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if !strings.Contains(output.String(), "Retrying Umbrella API request") || !strings.Contains(output.String(), `"resource":"umbrella_site"`) {
+		t.Errorf("expected the retry to be logged with the request logger fields, got %q", output.String())
+	}
+}
+
+func TestRetryTransportRetriesPostOnlyWhenUnprocessed(t *testing.T) {
+	for status, attempts := range map[int]int32{
+		http.StatusTooManyRequests:    2,
+		http.StatusServiceUnavailable: 2,
+		http.StatusBadGateway:         1,
+		http.StatusGatewayTimeout:     1,
+	} {
+		server, calls := testRetryServer(t, 1, status, "")
+
+		req, _ := http.NewRequest("POST", server.URL, strings.NewReader(`{"name":"one"}`))
+		res, err := testRetryClient(4, time.Second).Do(req)
+		if err != nil {
+			t.Fatalf("%d: %s", status, err)
+		}
+		res.Body.Close()
+
+		if *calls != attempts {
+			t.Errorf("%d: expected %d attempts, got %d", status, attempts, *calls)
+		}
+	}
+}
+
+func TestRetryTransportGivesUpAfterMaxRetries(t *testing.T) {
+	server, calls := testRetryServer(t, 10, http.StatusServiceUnavailable, "")
+
+	res, err := testRetryClient(2, time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the last 503 to be returned, got %d", res.StatusCode)
+	}
+	if *calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", *calls)
+	}
+}
+
+func TestRetryTransportDoesNotRetryOtherStatus(t *testing.T) {
+	server, calls := testRetryServer(t, 1, http.StatusInternalServerError, "")
+
+	res, err := testRetryClient(4, time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusInternalServerError || *calls != 1 {
+		t.Errorf("expected a single 500, got %d after %d attempts", res.StatusCode, *calls)
+	}
+}
+
+func TestRetryTransportHonoursRetryAfter(t *testing.T) {
+	server, calls := testRetryServer(t, 1, http.StatusTooManyRequests, "1")
+
+	start := time.Now()
+	res, err := testRetryClient(4, 5*time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || *calls != 2 {
+		t.Errorf("expected success on the second attempt, got %d after %d attempts", res.StatusCode, *calls)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait for Retry-After, waited %s", elapsed)
+	}
+}
+
+func TestRetryTransportRetryAfterBeyondMaxWait(t *testing.T) {
+	server, calls := testRetryServer(t, 1, http.StatusTooManyRequests, "120")
+
+	res, err := testRetryClient(4, time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusTooManyRequests || *calls != 1 {
+		t.Errorf("expected the 429 to be returned without retrying, got %d after %d attempts", res.StatusCode, *calls)
+	}
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	transport := &retryTransport{policy: retryPolicy{maxRetries: 10, maxWait: 8 * time.Second, baseWait: time.Second}}
+	res := &http.Response{Header: http.Header{}}
+
+	for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second} {
+		wait, ok := transport.wait(res, attempt)
+		if !ok || wait < max/2 || wait > max {
+			t.Errorf("attempt %d: expected a wait between %s and %s, got %s", attempt, max/2, max, wait)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("3"); !ok || wait != 3*time.Second {
+		t.Errorf("seconds: got %s %t", wait, ok)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if wait, ok := parseRetryAfter(date); !ok || wait <= 50*time.Second || wait > time.Minute {
+		t.Errorf("date: got %s %t", wait, ok)
+	}
+	for _, value := range []string{"", "-1", "soon"} {
+		if _, ok := parseRetryAfter(value); ok {
+			t.Errorf("%q: expected no Retry-After", value)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RoamingComputersDataSource{}
+var _ datasource.DataSourceWithConfigure = &RoamingComputersDataSource{}
+
+func NewRoamingComputersDataSource() datasource.DataSource {
+	return &RoamingComputersDataSource{}
+}
+
+type RoamingComputersDataSource struct {
+	client *apiClient
+}
+
+// RoamingComputersDataSourceModel describes the data source data model.
+type RoamingComputersDataSourceModel struct {
+	ID             types.String           `tfsdk:"id"`
+	Name           types.String           `tfsdk:"name"`
+	Tag            types.String           `tfsdk:"tag"`
+	Status         types.String           `tfsdk:"status"`
+	OsVersion      types.String           `tfsdk:"os_version"`
+	LastSyncAfter  types.String           `tfsdk:"last_sync_after"`
+	LastSyncBefore types.String           `tfsdk:"last_sync_before"`
+	Computers      []RoamingComputerModel `tfsdk:"computers"`
+}
+
+type RoamingComputerModel struct {
+	OriginId       types.Int64  `tfsdk:"origin_id"`
+	DeviceId       types.String `tfsdk:"device_id"`
+	Name           types.String `tfsdk:"name"`
+	Tags           []string     `tfsdk:"tags"`
+	Type           types.String `tfsdk:"type"`
+	Status         types.String `tfsdk:"status"`
+	SwgStatus      types.String `tfsdk:"swg_status"`
+	LastSyncStatus types.String `tfsdk:"last_sync_status"`
+	LastSync       types.String `tfsdk:"last_sync"`
+	Version        types.String `tfsdk:"version"`
+	OsVersion      types.String `tfsdk:"os_version"`
+	OsVersionName  types.String `tfsdk:"os_version_name"`
+}
+
+// validTimestamp checks that a filter is an RFC 3339 timestamp.
+var validTimestamp = stringCheck("value must be an RFC 3339 timestamp", func(value string) error {
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		return fmt.Errorf("%q is not an RFC 3339 timestamp, such as 2023-01-02T15:04:05Z", value)
+	}
+	return nil
+})
+
+func (d *RoamingComputersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_roaming_computers"
+}
+
+func (d *RoamingComputersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Roaming computers data source. Lists the computers registered through the Secure Client roaming module, optionally filtered",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Placeholder identifier of the data source",
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only return the roaming computers with this exact name",
+				Optional:            true,
+			},
+			"tag": schema.StringAttribute{
+				MarkdownDescription: "Only return the roaming computers with the tag of this name",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return the roaming computers with this DNS protection status, compared case-insensitively",
+				Optional:            true,
+			},
+			"os_version": schema.StringAttribute{
+				MarkdownDescription: "Only return the roaming computers whose operating system version or name starts with this value",
+				Optional:            true,
+			},
+			"last_sync_after": schema.StringAttribute{
+				MarkdownDescription: "Only return the roaming computers that last synced at or after this RFC 3339 timestamp",
+				Optional:            true,
+				Validators: []validator.String{
+					validTimestamp,
+				},
+			},
+			"last_sync_before": schema.StringAttribute{
+				MarkdownDescription: "Only return the roaming computers that last synced before this RFC 3339 timestamp, or never synced",
+				Optional:            true,
+				Validators: []validator.String{
+					validTimestamp,
+				},
+			},
+			"computers": schema.ListNestedAttribute{
+				MarkdownDescription: "The roaming computers matching all filters",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"origin_id": schema.Int64Attribute{
+							MarkdownDescription: "The origin ID of the roaming computer",
+							Computed:            true,
+						},
+						"device_id": schema.StringAttribute{
+							MarkdownDescription: "The device ID of the roaming computer",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the roaming computer",
+							Computed:            true,
+						},
+						"tags": schema.ListAttribute{
+							MarkdownDescription: "The names of the tags of the roaming computer",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The type of the roaming computer",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "The DNS protection status of the roaming computer",
+							Computed:            true,
+						},
+						"swg_status": schema.StringAttribute{
+							MarkdownDescription: "The Secure Web Gateway protection status of the roaming computer",
+							Computed:            true,
+						},
+						"last_sync_status": schema.StringAttribute{
+							MarkdownDescription: "The DNS protection status at the last sync",
+							Computed:            true,
+						},
+						"last_sync": schema.StringAttribute{
+							MarkdownDescription: "The date and time (ISO8601 timestamp) when the roaming computer last synced",
+							Computed:            true,
+						},
+						"version": schema.StringAttribute{
+							MarkdownDescription: "The version of the roaming module",
+							Computed:            true,
+						},
+						"os_version": schema.StringAttribute{
+							MarkdownDescription: "The operating system version of the roaming computer",
+							Computed:            true,
+						},
+						"os_version_name": schema.StringAttribute{
+							MarkdownDescription: "The operating system name of the roaming computer",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RoamingComputersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// matchRoamingComputer reports whether computer passes every filter set in the data source configuration.
+func matchRoamingComputer(data RoamingComputersDataSourceModel, computer RoamingComputer) bool {
+	if !data.Name.IsNull() && computer.Name != data.Name.ValueString() {
+		return false
+	}
+	if !data.Status.IsNull() && !strings.EqualFold(computer.Status, data.Status.ValueString()) {
+		return false
+	}
+	if !data.Tag.IsNull() {
+		tagged := false
+		for _, tag := range computer.Tags {
+			if tag.Name == data.Tag.ValueString() {
+				tagged = true
+			}
+		}
+		if !tagged {
+			return false
+		}
+	}
+	if !data.OsVersion.IsNull() {
+		prefix := data.OsVersion.ValueString()
+		if !strings.HasPrefix(computer.OsVersion, prefix) && !strings.HasPrefix(computer.OsVersionName, prefix) {
+			return false
+		}
+	}
+	if !data.LastSyncAfter.IsNull() || !data.LastSyncBefore.IsNull() {
+		// A computer that never synced, or whose last sync cannot be parsed,
+		// only matches last_sync_before.
+		lastSync, err := time.Parse(time.RFC3339, computer.LastSync)
+		if !data.LastSyncAfter.IsNull() {
+			after, _ := time.Parse(time.RFC3339, data.LastSyncAfter.ValueString())
+			if err != nil || lastSync.Before(after) {
+				return false
+			}
+		}
+		if !data.LastSyncBefore.IsNull() {
+			before, _ := time.Parse(time.RFC3339, data.LastSyncBefore.ValueString())
+			if err == nil && !lastSync.Before(before) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (d *RoamingComputersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RoamingComputersDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	computers, err := d.client.GetRoamingComputers(nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read Umbrella Roaming Computers", "Could not list Umbrella Roaming Computers", err, nil)
+		return
+	}
+
+	data.Computers = []RoamingComputerModel{}
+	for _, computer := range computers {
+		if !matchRoamingComputer(data, computer) {
+			continue
+		}
+		tags := make([]string, len(computer.Tags))
+		for i, tag := range computer.Tags {
+			tags[i] = tag.Name
+		}
+		data.Computers = append(data.Computers, RoamingComputerModel{
+			OriginId:       types.Int64Value(computer.OriginId),
+			DeviceId:       types.StringValue(computer.DeviceId),
+			Name:           types.StringValue(computer.Name),
+			Tags:           tags,
+			Type:           types.StringValue(computer.Type),
+			Status:         types.StringValue(computer.Status),
+			SwgStatus:      types.StringValue(computer.SwgStatus),
+			LastSyncStatus: types.StringValue(computer.LastSyncStatus),
+			LastSync:       types.StringValue(computer.LastSync),
+			Version:        types.StringValue(computer.Version),
+			OsVersion:      types.StringValue(computer.OsVersion),
+			OsVersionName:  types.StringValue(computer.OsVersionName),
+		})
+	}
+
+	data.ID = types.StringValue("roaming_computers")
+
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,70 @@
+package umbrellaprovider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRoamingComputersDataSource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.addTag(0, "finance")
+	fake.addRoamingComputer(0, RoamingComputer{Name: "LAPTOP-0001", Status: "Protected", OsVersion: "10.0.19045", OsVersionName: "Windows 10", LastSync: "2023-01-10T09:00:00Z"})
+	fake.addRoamingComputer(0, RoamingComputer{Name: "LAPTOP-0002", Status: "Off", OsVersion: "13.2.1", OsVersionName: "macOS Ventura", LastSync: "2023-03-01T09:00:00Z"})
+	stale := fake.addRoamingComputer(0, RoamingComputer{Name: "LAPTOP-0003", Status: "Unprotected", OsVersion: "10.0.22621", OsVersionName: "Windows 11"})
+	fake.addRoamingComputer(4321, RoamingComputer{Name: "LAPTOP-0004"})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_roaming_computer" "tagged" {
+  device_id         = "` + stale.DeviceId + `"
+  tags              = ["finance"]
+  forget_on_destroy = true
+}
+
+data "umbrella_roaming_computers" "all" {
+  depends_on = [umbrella_roaming_computer.tagged]
+}
+
+data "umbrella_roaming_computers" "finance" {
+  tag        = "finance"
+  depends_on = [umbrella_roaming_computer.tagged]
+}
+
+data "umbrella_roaming_computers" "windows" {
+  os_version = "Windows"
+  status     = "protected"
+}
+
+data "umbrella_roaming_computers" "stale" {
+  last_sync_before = "2023-02-01T00:00:00Z"
+}
+
+data "umbrella_roaming_computers" "recent" {
+  last_sync_after = "2023-02-01T00:00:00Z"
+}
+
+data "umbrella_roaming_computers" "named" {
+  name = "LAPTOP-0002"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.all", "computers.#", "3"),
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.finance", "computers.#", "1"),
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.finance", "computers.0.name", "LAPTOP-0003"),
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.finance", "computers.0.tags.0", "finance"),
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.windows", "computers.#", "1"),
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.windows", "computers.0.name", "LAPTOP-0001"),
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.stale", "computers.#", "2"),
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.recent", "computers.#", "1"),
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.recent", "computers.0.name", "LAPTOP-0002"),
+					resource.TestCheckResourceAttr("data.umbrella_roaming_computers.named", "computers.0.os_version_name", "macOS Ventura"),
+				),
+			},
+		},
+	})
+}
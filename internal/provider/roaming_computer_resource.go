@@ -0,0 +1,519 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RoamingComputerResource{}
+var _ resource.ResourceWithImportState = &RoamingComputerResource{}
+
+func NewRoamingComputerResource() resource.Resource {
+	return &RoamingComputerResource{}
+}
+
+// RoamingComputerResource defines the resource implementation. Roaming
+// computers register themselves when the roaming module is installed, so the
+// resource adopts an existing computer instead of creating one.
+type RoamingComputerResource struct {
+	client *apiClient
+}
+
+// RoamingComputerResourceModel describes the resource data model.
+type RoamingComputerResourceModel struct {
+	ID              types.Int64  `tfsdk:"id"`
+	OriginId        types.Int64  `tfsdk:"origin_id"`
+	DeviceId        types.String `tfsdk:"device_id"`
+	Name            types.String `tfsdk:"name"`
+	Tags            types.Set    `tfsdk:"tags"`
+	Type            types.String `tfsdk:"type"`
+	Status          types.String `tfsdk:"status"`
+	SwgStatus       types.String `tfsdk:"swg_status"`
+	LastSyncStatus  types.String `tfsdk:"last_sync_status"`
+	LastSync        types.String `tfsdk:"last_sync"`
+	Version         types.String `tfsdk:"version"`
+	OsVersion       types.String `tfsdk:"os_version"`
+	OsVersionName   types.String `tfsdk:"os_version_name"`
+	LastUpdated     types.String `tfsdk:"last_updated"`
+	OrgId           types.Int64  `tfsdk:"org_id"`
+	ForgetOnDestroy types.Bool   `tfsdk:"forget_on_destroy"`
+	ForceDelete     types.Bool   `tfsdk:"force_delete"`
+}
+
+func (r *RoamingComputerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_roaming_computer"
+}
+
+func (r *RoamingComputerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Roaming computer resource. Adopts a computer that registered through the Secure Client roaming module and manages its name and tags",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"origin_id": schema.Int64Attribute{
+				MarkdownDescription: "The origin ID of the roaming computer",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"device_id": schema.StringAttribute{
+				MarkdownDescription: "The device ID of the registered roaming computer to adopt",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the roaming computer. Defaults to the name it registered with",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags": schema.SetAttribute{
+				MarkdownDescription: "The names of the tags of the roaming computer. The tags must exist. Tags are left alone when this is not set",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the roaming computer",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The DNS protection status of the roaming computer",
+				Computed:            true,
+			},
+			"swg_status": schema.StringAttribute{
+				MarkdownDescription: "The Secure Web Gateway protection status of the roaming computer",
+				Computed:            true,
+			},
+			"last_sync_status": schema.StringAttribute{
+				MarkdownDescription: "The DNS protection status at the last sync",
+				Computed:            true,
+			},
+			"last_sync": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the roaming computer last synced",
+				Computed:            true,
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "The version of the roaming module",
+				Computed:            true,
+			},
+			"os_version": schema.StringAttribute{
+				MarkdownDescription: "The operating system version of the roaming computer",
+				Computed:            true,
+			},
+			"os_version_name": schema.StringAttribute{
+				MarkdownDescription: "The operating system name of the roaming computer",
+				Computed:            true,
+			},
+			"forget_on_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Only remove the roaming computer from the Terraform state on destroy, leaving it registered in Umbrella",
+				Optional:            true,
+			},
+			"force_delete": schema.BoolAttribute{
+				MarkdownDescription: "Delete the roaming computer on destroy even if its status shows the roaming module is still active",
+				Optional:            true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the roaming computer. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (r *RoamingComputerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *RoamingComputerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RoamingComputerResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+	deviceID := data.DeviceId.ValueString()
+
+	computer, err := api.GetRoamingComputer(deviceID, nil)
+	if isNotFound(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("device_id"),
+			"Roaming Computer Not Found",
+			fmt.Sprintf("No roaming computer with device ID %q is registered in Umbrella. "+
+				"Roaming computers cannot be created through the Umbrella API, they register when the roaming module is installed.", deviceID),
+		)
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Roaming Computer", "Could not read Umbrella Roaming Computer "+deviceID+" to adopt it", err, nil)
+		return
+	}
+
+	tflog.Info(ctx, "Adopting registered Umbrella roaming computer", map[string]interface{}{
+		"device_id": deviceID,
+		"origin_id": computer.OriginId,
+	})
+
+	computer, diags := r.apply(ctx, api, data, computer)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setRoamingComputerModel(ctx, data, computer)...)
+	data.OrgId = api.orgIDValue()
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RoamingComputerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *RoamingComputerResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	computer, err := api.GetRoamingComputer(data.DeviceId.ValueString(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Roaming Computer no longer exists, removing it from state", map[string]interface{}{
+			"device_id": data.DeviceId.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Roaming Computer", "Could not read Umbrella Roaming Computer "+data.DeviceId.ValueString(), err, nil)
+		return
+	}
+
+	resp.Diagnostics.Append(setRoamingComputerModel(ctx, data, computer)...)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RoamingComputerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *RoamingComputerResourceModel
+	var statedata *RoamingComputerResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &statedata)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(statedata.OrgId).withContext(ctx)
+
+	computer, err := api.GetRoamingComputer(statedata.DeviceId.ValueString(), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Roaming Computer", "Could not read Umbrella Roaming Computer "+statedata.DeviceId.ValueString(), err, nil)
+		return
+	}
+
+	computer, diags := r.apply(ctx, api, data, computer)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setRoamingComputerModel(ctx, data, computer)...)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// apply renames and retags computer as planned in data, and returns the
+// computer as it is afterwards.
+func (r *RoamingComputerResource) apply(ctx context.Context, api *apiClient, data *RoamingComputerResourceModel, computer *RoamingComputer) (*RoamingComputer, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	deviceID := computer.DeviceId
+
+	if !data.Name.IsUnknown() && !data.Name.IsNull() && data.Name.ValueString() != computer.Name {
+		_, err := api.RenameRoamingComputer(deviceID, data.Name.ValueString(), nil)
+		if err != nil {
+			addAPIError(&diags, "Error Updating Umbrella Roaming Computer", "Could not rename Umbrella Roaming Computer "+deviceID, err, apiErrorPaths{http.StatusBadRequest: path.Root("name")})
+			return nil, diags
+		}
+	}
+
+	if !data.Tags.IsUnknown() && !data.Tags.IsNull() {
+		var want []string
+		diags.Append(data.Tags.ElementsAs(ctx, &want, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		diags.Append(setDeviceTags(api, computer.OriginId, computer.Tags, want)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	computer, err := api.GetRoamingComputer(deviceID, nil)
+	if err != nil {
+		addAPIError(&diags, "Error Reading Umbrella Roaming Computer", "Could not read Umbrella Roaming Computer "+deviceID, err, nil)
+		return nil, diags
+	}
+
+	return computer, diags
+}
+
+// setDeviceTags adds and removes tags of the device with originID so that
+// it has exactly the tags named in want.
+func setDeviceTags(api *apiClient, originID int64, current []Tag, want []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	wanted := map[string]bool{}
+	for _, name := range want {
+		wanted[name] = true
+	}
+	has := map[string]bool{}
+	for _, tag := range current {
+		has[tag.Name] = true
+		if !wanted[tag.Name] {
+			if err := api.RemoveTagDevices(tag.Id, []int64{originID}, nil); err != nil {
+				addAPIError(&diags, "Error Updating Umbrella Roaming Computer Tags", fmt.Sprintf("Could not remove tag %q from origin ID %d", tag.Name, originID), err, nil)
+				return diags
+			}
+		}
+	}
+
+	var missing []string
+	for _, name := range want {
+		if !has[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return diags
+	}
+
+	tags, err := api.GetTags(nil)
+	if err != nil {
+		addAPIError(&diags, "Error Updating Umbrella Roaming Computer Tags", "Could not list Umbrella Tags", err, nil)
+		return diags
+	}
+	tagIDs := map[string]int64{}
+	for _, tag := range tags {
+		tagIDs[tag.Name] = tag.Id
+	}
+
+	sort.Strings(missing)
+	for _, name := range missing {
+		tagID, ok := tagIDs[name]
+		if !ok {
+			diags.AddAttributeError(
+				path.Root("tags"),
+				"Unknown Umbrella Tag",
+				fmt.Sprintf("No Umbrella tag is named %q. Create the tag before assigning it.", name),
+			)
+			return diags
+		}
+		if err := api.AddTagDevices(tagID, []int64{originID}, nil); err != nil {
+			addAPIError(&diags, "Error Updating Umbrella Roaming Computer Tags", fmt.Sprintf("Could not add tag %q to origin ID %d", name, originID), err, nil)
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func (r *RoamingComputerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *RoamingComputerResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := data.DeviceId.ValueString()
+
+	if data.ForgetOnDestroy.ValueBool() {
+		tflog.Info(ctx, "Removing Umbrella roaming computer from state only, as forget_on_destroy is set", map[string]interface{}{
+			"device_id": deviceID,
+		})
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	// The status in state may be stale, so check the computer as it is now.
+	computer, err := api.GetRoamingComputer(deviceID, nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Roaming Computer is already gone", map[string]interface{}{
+			"device_id": deviceID,
+		})
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Roaming Computer", "Could not read Umbrella Roaming Computer "+deviceID+" before deleting it", err, nil)
+		return
+	}
+
+	if roamingComputerActive(computer.Status) && !data.ForceDelete.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Umbrella Roaming Computer Still Active",
+			fmt.Sprintf("Umbrella Roaming Computer %s (%s) reports status %q, so the roaming module is still installed and would register it again. "+
+				"Uninstall the roaming module before destroying it, set force_delete = true to delete it anyway, "+
+				"or set forget_on_destroy = true to only remove it from the Terraform state.", deviceID, computer.Name, computer.Status),
+		)
+		return
+	}
+
+	err = api.DeleteRoamingComputer(deviceID, nil)
+	if isNotFound(err) {
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Roaming Computer", "Could not delete Umbrella Roaming Computer "+deviceID, err, nil)
+		return
+	}
+}
+
+// roamingComputerActive reports whether a roaming computer with status still
+// runs the roaming module. Computers that were switched off or uninstalled are
+// inactive.
+func roamingComputerActive(status string) bool {
+	switch strings.ToLower(status) {
+	case "", "off", "uninstalled":
+		return false
+	}
+	return true
+}
+
+func (r *RoamingComputerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	orgid, deviceID, err := splitImportOrgID(req.ID)
+	if err == nil && deviceID == "" {
+		err = fmt.Errorf("empty device ID in import ID %q, expected <device_id>, name:<name> or <org_id>/<device_id>", req.ID)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Umbrella Roaming Computer Import ID", err.Error())
+		return
+	}
+
+	if strings.HasPrefix(deviceID, importNamePrefix) {
+		name := strings.TrimPrefix(deviceID, importNamePrefix)
+		computers, err := r.client.forOrg(orgid).withContext(ctx).GetRoamingComputers(nil)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to Resolve Umbrella Roaming Computer Import ID", fmt.Sprintf("Could not look up the Umbrella Roaming Computer named %q", name), err, nil)
+			return
+		}
+
+		var matches []string
+		for _, computer := range computers {
+			if computer.Name == name {
+				matches = append(matches, computer.DeviceId)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			resp.Diagnostics.AddError("Invalid Umbrella Roaming Computer Import ID", fmt.Sprintf("No Umbrella Roaming Computer is named %q.", name))
+			return
+		case 1:
+			deviceID = matches[0]
+		default:
+			sort.Strings(matches)
+			resp.Diagnostics.AddError("Invalid Umbrella Roaming Computer Import ID", fmt.Sprintf("%d Umbrella Roaming Computers are named %q. Import one of them by device ID instead: %s.", len(matches), name, strings.Join(matches, ", ")))
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("device_id"), deviceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
+}
+
+func setRoamingComputerModel(ctx context.Context, data *RoamingComputerResourceModel, computer *RoamingComputer) diag.Diagnostics {
+	tags := make([]string, len(computer.Tags))
+	for i, tag := range computer.Tags {
+		tags[i] = tag.Name
+	}
+	tagSet, diags := types.SetValueFrom(ctx, types.StringType, tags)
+
+	data.ID = types.Int64Value(computer.OriginId)
+	data.OriginId = types.Int64Value(computer.OriginId)
+	data.DeviceId = types.StringValue(computer.DeviceId)
+	data.Name = types.StringValue(computer.Name)
+	data.Tags = tagSet
+	data.Type = types.StringValue(computer.Type)
+	data.Status = types.StringValue(computer.Status)
+	data.SwgStatus = types.StringValue(computer.SwgStatus)
+	data.LastSyncStatus = types.StringValue(computer.LastSyncStatus)
+	data.LastSync = types.StringValue(computer.LastSync)
+	data.Version = types.StringValue(computer.Version)
+	data.OsVersion = types.StringValue(computer.OsVersion)
+	data.OsVersionName = types.StringValue(computer.OsVersionName)
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	return diags
+}
@@ -0,0 +1,147 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRoamingComputerResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.addTag(0, "finance")
+	fake.addTag(0, "engineering")
+	computer := fake.addRoamingComputer(0, RoamingComputer{Name: "LAPTOP-0042", Status: "Off", OsVersion: "10.0.19045"})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if fake.hasRoamingComputer(computer.DeviceId) {
+				return fmt.Errorf("roaming computer %s still exists", computer.DeviceId)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Adopt
+			{
+				Config: testAccRoamingComputerResourceConfig(fake, computer.DeviceId, `
+  name = "finance-laptop-42"
+  tags = ["finance"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_roaming_computer.test", "origin_id", strconv.FormatInt(computer.OriginId, 10)),
+					resource.TestCheckResourceAttr("umbrella_roaming_computer.test", "name", "finance-laptop-42"),
+					resource.TestCheckResourceAttr("umbrella_roaming_computer.test", "tags.#", "1"),
+					resource.TestCheckTypeSetElemAttr("umbrella_roaming_computer.test", "tags.*", "finance"),
+					resource.TestCheckResourceAttr("umbrella_roaming_computer.test", "os_version", "10.0.19045"),
+				),
+			},
+			// Retag
+			{
+				Config: testAccRoamingComputerResourceConfig(fake, computer.DeviceId, `
+  name = "finance-laptop-42"
+  tags = ["engineering"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_roaming_computer.test", "tags.#", "1"),
+					resource.TestCheckTypeSetElemAttr("umbrella_roaming_computer.test", "tags.*", "engineering"),
+				),
+			},
+			// Leaving tags out keeps them
+			{
+				Config: testAccRoamingComputerResourceConfig(fake, computer.DeviceId, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_roaming_computer.test", "name", "finance-laptop-42"),
+					resource.TestCheckResourceAttr("umbrella_roaming_computer.test", "tags.#", "1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "umbrella_roaming_computer.test",
+				ImportState:             true,
+				ImportStateId:           computer.DeviceId,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			{
+				ResourceName:            "umbrella_roaming_computer.test",
+				ImportState:             true,
+				ImportStateId:           "name:finance-laptop-42",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			{
+				ResourceName:  "umbrella_roaming_computer.test",
+				ImportState:   true,
+				ImportStateId: "name:LAPTOP-0042",
+				ExpectError:   regexp.MustCompile(`No Umbrella Roaming Computer is named "LAPTOP-0042"`),
+			},
+		},
+	})
+}
+
+func TestAccRoamingComputerResource_notRegistered(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.addTag(0, "finance")
+	computer := fake.addRoamingComputer(0, RoamingComputer{Name: "LAPTOP-0042"})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRoamingComputerResourceConfig(fake, "0123456789abcdef", ""),
+				ExpectError: regexp.MustCompile(`Roaming Computer Not Found`),
+			},
+			{
+				Config: testAccRoamingComputerResourceConfig(fake, computer.DeviceId, `
+  tags              = ["marketing"]
+  forget_on_destroy = true`),
+				ExpectError: regexp.MustCompile(`No Umbrella tag is named "marketing"`),
+			},
+		},
+	})
+}
+
+func TestAccRoamingComputerResource_stillActive(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	computer := fake.addRoamingComputer(0, RoamingComputer{Name: "LAPTOP-0042", Status: "Protected"})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoamingComputerResourceConfig(fake, computer.DeviceId, ""),
+				Check:  resource.TestCheckResourceAttr("umbrella_roaming_computer.test", "name", "LAPTOP-0042"),
+			},
+			{
+				Config:      fake.providerConfig(),
+				ExpectError: regexp.MustCompile(`Umbrella Roaming Computer Still Active`),
+			},
+			{
+				Config: testAccRoamingComputerResourceConfig(fake, computer.DeviceId, `
+  force_delete = true`),
+			},
+			{
+				Config: fake.providerConfig(),
+				Check: func(*terraform.State) error {
+					if fake.hasRoamingComputer(computer.DeviceId) {
+						return fmt.Errorf("roaming computer %s still exists", computer.DeviceId)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccRoamingComputerResourceConfig(fake *fakeUmbrella, deviceID string, extra string) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_roaming_computer" "test" {
+  device_id = %q%s
+}
+`, deviceID, extra)
+}
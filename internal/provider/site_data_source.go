@@ -8,7 +8,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/olegunza/umbrella-api-go/umbrella"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -20,7 +19,7 @@ func NewSiteDataSource() datasource.DataSource {
 }
 
 type SiteDataSource struct {
-	client *umbrella.Client
+	client *apiClient
 }
 
 // ExampleDataSourceModel describes the data source data model.
@@ -95,12 +94,12 @@ func (d *SiteDataSource) Configure(ctx context.Context, req datasource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*umbrella.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -119,13 +118,10 @@ func (d *SiteDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	sites, err := d.client.GetSites(&d.client.Token)
+	sites, err := d.client.GetSites(nil)
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Umbrella Sites",
-			err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, "Unable to Read Umbrella Sites", "Could not list Umbrella Sites", err, nil)
 		return
 	}
 
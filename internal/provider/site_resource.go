@@ -3,12 +3,16 @@ package umbrellaprovider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/olegunza/umbrella-api-go/umbrella"
@@ -24,19 +28,21 @@ func NewSiteResource() resource.Resource {
 
 // ExampleResource defines the resource implementation.
 type SiteResource struct {
-	client *umbrella.Client
+	client *apiClient
 }
 
 // ExampleResourceModel describes the resource data model.
 type SiteResourceModel struct {
-	SiteId      types.Int64  `tfsdk:"site_id"`
-	LastUpdated types.String `tfsdk:"last_updated"`
-	OriginId    types.Int64  `tfsdk:"origin_id"`
-	IsDefault   types.Bool   `tfsdk:"is_default"`
-	Name        types.String `tfsdk:"name"`
-	ModifiedAt  types.String `tfsdk:"modified_at"`
-	CreatedAt   types.String `tfsdk:"created_at"`
-	ID          types.Int64  `tfsdk:"id"`
+	SiteId      types.Int64    `tfsdk:"site_id"`
+	LastUpdated types.String   `tfsdk:"last_updated"`
+	OriginId    types.Int64    `tfsdk:"origin_id"`
+	IsDefault   types.Bool     `tfsdk:"is_default"`
+	Name        types.String   `tfsdk:"name"`
+	ModifiedAt  types.String   `tfsdk:"modified_at"`
+	CreatedAt   types.String   `tfsdk:"created_at"`
+	ID          types.Int64    `tfsdk:"id"`
+	OrgId       types.Int64    `tfsdk:"org_id"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *SiteResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -79,6 +85,24 @@ func (r *SiteResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				MarkdownDescription: "The date and time (ISO8601 timestamp) when the Site was created",
 				Computed:            true,
 			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the Site. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -89,12 +113,12 @@ func (r *SiteResource) Configure(ctx context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	client, ok := req.ProviderData.(*umbrella.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -113,17 +137,29 @@ func (r *SiteResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
 	siteItem := umbrella.Site{
 		Name: data.Name.ValueString(),
 	}
 
-	site, err := r.client.CreateSite(siteItem, &r.client.Token)
+	site, err := api.CreateSite(siteItem, nil)
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating Site",
-			"Could not create Site, unexpected error: "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "create", "Umbrella Site", "", createTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Creating Umbrella Site", "Could not create Umbrella Site", err, apiErrorPaths{http.StatusConflict: path.Root("name")})
 		return
 	}
 
@@ -136,6 +172,7 @@ func (r *SiteResource) Create(ctx context.Context, req resource.CreateRequest, r
 	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	data.ID = types.Int64Value(int64(site.Siteid))
+	data.OrgId = api.orgIDValue()
 
 	tflog.Trace(ctx, "created a resource")
 
@@ -156,12 +193,31 @@ func (r *SiteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	site, err := r.client.GetSite(data.SiteId.ValueInt64(), &r.client.Token)
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	site, err := api.GetSite(data.SiteId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Site no longer exists, removing it from state", map[string]interface{}{
+			"site_id": data.SiteId.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Reading Umbrella Site",
-			"Could not read Umbrella Site ID "+strconv.FormatInt(data.SiteId.ValueInt64(), 10)+": "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "read", "Umbrella Site", strconv.FormatInt(data.SiteId.ValueInt64(), 10), readTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Site", "Could not read Umbrella Site ID "+strconv.FormatInt(data.SiteId.ValueInt64(), 10), err, nil)
 		return
 	}
 
@@ -173,6 +229,7 @@ func (r *SiteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	data.ID = types.Int64Value(int64(site.Siteid))
+	data.OrgId = api.orgIDValue()
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -192,26 +249,39 @@ func (r *SiteResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	api := r.client.forOrg(statedata.OrgId).withContext(ctx)
+
 	//siteid, _ := strconv.Atoi(data.SiteId.ValueString())
 	siteItem := umbrella.Site{
 		Name: data.Name.ValueString(),
 	}
 
-	_, err := r.client.UpdateSite(statedata.SiteId.ValueInt64(), siteItem, &r.client.Token)
+	_, err := api.UpdateSite(statedata.SiteId.ValueInt64(), siteItem, nil)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating Umbrella Site"+strconv.FormatInt(statedata.SiteId.ValueInt64(), 10),
-			"Could not update order, unexpected error: "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "update", "Umbrella Site", strconv.FormatInt(statedata.SiteId.ValueInt64(), 10), updateTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Updating Umbrella Site", "Could not update Umbrella Site ID "+strconv.FormatInt(statedata.SiteId.ValueInt64(), 10), err, apiErrorPaths{http.StatusConflict: path.Root("name")})
 		return
 	}
 
-	site, err := r.client.GetSite(statedata.SiteId.ValueInt64(), &r.client.Token)
+	site, err := api.GetSite(statedata.SiteId.ValueInt64(), nil)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Reading Umbrella Site",
-			"Could not read Umbrella Site ID "+strconv.FormatInt(statedata.SiteId.ValueInt64(), 10)+": "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "update", "Umbrella Site", strconv.FormatInt(statedata.SiteId.ValueInt64(), 10), updateTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Site", "Could not read Umbrella Site ID "+strconv.FormatInt(statedata.SiteId.ValueInt64(), 10), err, nil)
 		return
 	}
 
@@ -224,6 +294,7 @@ func (r *SiteResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	data.ID = types.Int64Value(int64(site.Siteid))
+	data.OrgId = api.orgIDValue()
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -241,20 +312,53 @@ func (r *SiteResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	err := r.client.DeleteSite(data.SiteId.ValueInt64(), &r.client.Token)
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.forOrg(data.OrgId).withContext(ctx).DeleteSite(data.SiteId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Site is already gone", map[string]interface{}{
+			"site_id": data.SiteId.ValueInt64(),
+		})
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Deleting Umbrella Site",
-			"Could not delete order, unexpected error: "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "delete", "Umbrella Site", strconv.FormatInt(data.SiteId.ValueInt64(), 10), deleteTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Site", "Could not delete Umbrella Site ID "+strconv.FormatInt(data.SiteId.ValueInt64(), 10), err, nil)
 		return
 	}
 }
 
 func (r *SiteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 
-	siteid, _ := strconv.ParseInt(req.ID, 10, 64)
+	orgid, siteid, diags := resolveImportID(r.client, req.ID, "Site", func(api *apiClient, name string) ([]int64, error) {
+		sites, err := api.GetSites(nil)
+		if err != nil {
+			return nil, err
+		}
+		var ids []int64
+		for _, site := range sites {
+			if site.Name == name {
+				ids = append(ids, int64(site.Siteid))
+			}
+		}
+		return ids, nil
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("site_id"), siteid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
 	//resource.ImportStatePassthroughID(ctx, path.Root("site_id"), req, resp)
 }
@@ -2,57 +2,308 @@ package umbrellaprovider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccSiteResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var siteID string
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// Create and Read testing
 			{
-				Config: testAccSiteResourceConfig("one"),
+				Config: testAccSiteResourceConfig(fake, "one"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("umbrella_site.test", "name", "one"),
 					// Verify dynamic values have any value set in the state.
 					resource.TestCheckResourceAttrSet("umbrella_site.test", "site_id"),
 					resource.TestCheckResourceAttrSet("umbrella_site.test", "origin_id"),
+					testAccCaptureAttr("umbrella_site.test", "site_id", &siteID),
 				),
 			},
 			// ImportState testing
 			{
 				ResourceName:            "umbrella_site.test",
 				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateID("umbrella_site.test", "site_id"),
 				ImportStateVerify:       true,
 				ImportStateVerifyIgnore: []string{"last_updated"},
 			},
 			// Update and Read testing
 			{
-				Config: testAccSiteResourceConfig("two"),
+				Config: testAccSiteResourceConfig(fake, "two"),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("umbrella_example.test", "name", "two"),
+					resource.TestCheckResourceAttr("umbrella_site.test", "name", "two"),
+					testAccCheckAttrUnchanged("umbrella_site.test", "site_id", &siteID),
 				),
 			},
 			// Delete testing automatically occurs in TestCase
 		},
+		CheckDestroy: func(s *terraform.State) error {
+			if names := fake.siteNames(0); len(names) != 0 {
+				return fmt.Errorf("sites left behind: %v", names)
+			}
+			return nil
+		},
+	})
+}
+
+func TestAccSiteResource_orgID(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_site" "test" {
+  name   = "child"
+  org_id = 1234
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_site.test", "org_id", "1234"),
+					func(s *terraform.State) error {
+						if names := fake.siteNames(1234); len(names) != 1 || names[0] != "child" {
+							return fmt.Errorf("expected site in org 1234, got %v", names)
+						}
+						if names := fake.siteNames(0); len(names) != 0 {
+							return fmt.Errorf("expected no site in the parent org, got %v", names)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName: "umbrella_site.test",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					id, err := testAccImportStateID("umbrella_site.test", "site_id")(s)
+					return "1234/" + id, err
+				},
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			{
+				ResourceName:            "umbrella_site.test",
+				ImportState:             true,
+				ImportStateId:           "1234/name:child",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Dropping org_id from the configuration keeps the site where it is.
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_site" "test" {
+  name = "child"
+}
+`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_importByName(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteResourceConfig(fake, "one"),
+			},
+			{
+				ResourceName:            "umbrella_site.test",
+				ImportState:             true,
+				ImportStateId:           "name:one",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			{
+				ResourceName:  "umbrella_site.test",
+				ImportState:   true,
+				ImportStateId: "name:two",
+				ExpectError:   regexp.MustCompile(`No Umbrella Site is named "two"`),
+			},
+			{
+				ResourceName:  "umbrella_site.test",
+				ImportState:   true,
+				ImportStateId: "4x2",
+				ExpectError:   regexp.MustCompile(`Invalid Umbrella Site Import ID`),
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_serverError(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.fail("POST", "/deployments/v2/sites", 500, 1)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccSiteResourceConfig(fake, "one"),
+				ExpectError: regexp.MustCompile(`Could not create Umbrella Site: Umbrella API returned 500`),
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_duplicateName(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteResourceConfig(fake, "one") + `
+resource "umbrella_site" "duplicate" {
+  name       = "one"
+  depends_on = [umbrella_site.test]
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)in resource "umbrella_site" "duplicate":\s+\d+:\s+name\s+= "one".*409 Conflict: A site\s+with the name one already exists.*Request ID: fake-tx-\d+`),
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_deletedOutsideTerraform(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var siteID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteResourceConfig(fake, "one"),
+				Check:  testAccCaptureAttr("umbrella_site.test", "site_id", &siteID),
+			},
+			// The refresh drops the missing site so that the plan recreates it.
+			{
+				PreConfig:          func() { fake.remove(siteID) },
+				Config:             testAccSiteResourceConfig(fake, "one"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccSiteResourceConfig(fake, "one"),
+				Check: func(s *terraform.State) error {
+					if names := fake.siteNames(0); len(names) != 1 {
+						return fmt.Errorf("expected the site to be recreated, got %v", names)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_deleteAlreadyGone(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteResourceConfig(fake, "one"),
+			},
+			{
+				PreConfig: func() { fake.fail("DELETE", "/deployments/v2/sites/", 404, 1) },
+				Config:    fake.providerConfig(),
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_timeout(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.stall("POST", "/deployments/v2/sites", 5*time.Second, 1)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+resource "umbrella_site" "test" {
+  name = "slow"
+
+  timeouts {
+    create = "1s"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Creating Umbrella Site did not finish within the create timeout of 1s`),
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_throttled(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.throttle("POST", "/deployments/v2/sites", 2, "0")
+	fake.fail("GET", "/deployments/v2/sites/", 503, 1)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteResourceConfig(fake, "one"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_site.test", "name", "one"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_throttledWithoutRetries(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.throttle("POST", "/deployments/v2/sites", 1, "0")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "umbrella" {
+  host        = %q
+  apikey      = "fake-key"
+  apisecret   = "fake-secret"
+  max_retries = 0
+}
+
+resource "umbrella_site" "test" {
+  name = "one"
+}
+`, fake.server.URL),
+				ExpectError: regexp.MustCompile(`Umbrella API returned 429 Too Many Requests`),
+			},
+		},
 	})
 }
 
-func testAccSiteResourceConfig(sitename string) string {
-	return fmt.Sprintf(`
-	terraform {
-		required_providers {
-		  umbrella = {
-			source  = "OZAKHARO-M-22C5.local/local/umbrella"
-			version = "1.0.0"
-		   
-		  }
-		}
-	  }
-	  
+func testAccSiteResourceConfig(fake *fakeUmbrella, sitename string) string {
+	return fake.providerConfig() + fmt.Sprintf(`
 resource "umbrella_site" "test" {
   name = %[1]q
 }
@@ -0,0 +1,494 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SWGDeviceSettingsResource{}
+
+// maxReportedDeviceFailures caps the devices listed in a diagnostic when many
+// devices fail at once.
+const maxReportedDeviceFailures = 10
+
+func NewSWGDeviceSettingsResource() resource.Resource {
+	return &SWGDeviceSettingsResource{}
+}
+
+// SWGDeviceSettingsResource defines the resource implementation. It owns the
+// SWGEnabled override of the devices it lists, and clears it on destroy so
+// that the devices follow the organization setting again.
+type SWGDeviceSettingsResource struct {
+	client *apiClient
+}
+
+// SWGDeviceSettingsResourceModel describes the resource data model.
+type SWGDeviceSettingsResourceModel struct {
+	Id          types.String `tfsdk:"id"`
+	OriginIds   types.Set    `tfsdk:"origin_ids"`
+	Enabled     types.Bool   `tfsdk:"enabled"`
+	Devices     types.List   `tfsdk:"devices"`
+	LastUpdated types.String `tfsdk:"last_updated"`
+	OrgId       types.Int64  `tfsdk:"org_id"`
+}
+
+type SWGDeviceModel struct {
+	OriginId   types.Int64  `tfsdk:"origin_id"`
+	Enabled    types.Bool   `tfsdk:"enabled"`
+	ModifiedAt types.String `tfsdk:"modified_at"`
+	SwgStatus  types.String `tfsdk:"swg_status"`
+}
+
+func (o SWGDeviceModel) attrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"origin_id":   types.Int64Type,
+		"enabled":     types.BoolType,
+		"modified_at": types.StringType,
+		"swg_status":  types.StringType,
+	}
+}
+
+func (r *SWGDeviceSettingsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_swg_device_settings"
+}
+
+func (r *SWGDeviceSettingsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "SWG device settings resource. Overrides whether roaming computers send web traffic to the Secure Web Gateway. " +
+			"Destroying it clears the override, so that the devices follow the organization setting again",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "A fingerprint of `origin_ids`, which changes with it",
+				Computed:            true,
+			},
+			"origin_ids": schema.SetAttribute{
+				MarkdownDescription: "The origin IDs of the roaming computers to override the setting of",
+				ElementType:         types.Int64Type,
+				Required:            true,
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the roaming computers send web traffic to the Secure Web Gateway",
+				Required:            true,
+			},
+			"devices": schema.ListNestedAttribute{
+				MarkdownDescription: "The override and protection status of every device in `origin_ids`, ordered by origin ID",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"origin_id": schema.Int64Attribute{
+							MarkdownDescription: "The origin ID of the roaming computer",
+							Computed:            true,
+						},
+						"enabled": schema.BoolAttribute{
+							MarkdownDescription: "The override of the roaming computer. Null when it has none and follows the organization setting",
+							Computed:            true,
+						},
+						"modified_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time (ISO8601 timestamp) when the override last changed",
+							Computed:            true,
+						},
+						"swg_status": schema.StringAttribute{
+							MarkdownDescription: "The Secure Web Gateway protection status the roaming computer reports. Null when it is not registered",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the roaming computers. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SWGDeviceSettingsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *SWGDeviceSettingsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SWGDeviceSettingsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	var originIDs []int64
+	resp.Diagnostics.Append(data.OriginIds.ElementsAs(ctx, &originIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// When some devices or batches fail, the state still records the devices
+	// that changed, so that destroying the resource clears their override.
+	results, err := api.SetDeviceSetting(swgEnabledSetting, swgSettingValue(data.Enabled.ValueBool()), originIDs, nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Setting Umbrella SWG Device Settings", fmt.Sprintf("Could not override the SWG setting of %d devices", len(originIDs)), err, nil)
+	}
+	addDeviceSettingFailures(&resp.Diagnostics, "Error Setting Umbrella SWG Device Settings", results)
+
+	changed := deviceSettingChanged(results)
+	var applied []int64
+	for _, originID := range originIDs {
+		if changed[originID] {
+			applied = append(applied, originID)
+		}
+	}
+	if len(applied) == 0 && resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSWGDeviceSettingsModel(ctx, api, data, applied)...)
+	data.OrgId = api.orgIDValue()
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SWGDeviceSettingsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SWGDeviceSettingsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	var originIDs []int64
+	resp.Diagnostics.Append(data.OriginIds.ElementsAs(ctx, &originIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, err := api.ListDeviceSettings(swgEnabledSetting, originIDs, nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella SWG Device Settings", fmt.Sprintf("Could not read the SWG setting of %d devices", len(originIDs)), err, nil)
+		return
+	}
+
+	// Devices whose override was changed or cleared outside Terraform drop
+	// out of origin_ids, so that the plan sets them again.
+	want := swgSettingValue(data.Enabled.ValueBool())
+	matched := []int64{}
+	for _, setting := range settings {
+		if setting.Value == want {
+			matched = append(matched, setting.OriginId)
+		}
+	}
+	if len(matched) != len(originIDs) {
+		tflog.Info(ctx, "Umbrella SWG device settings changed outside Terraform", map[string]interface{}{
+			"expected": len(originIDs),
+			"matched":  len(matched),
+		})
+	}
+	matchedSet, diags := types.SetValueFrom(ctx, types.Int64Type, matched)
+	resp.Diagnostics.Append(diags...)
+
+	devices, diags := swgDevicesFromSettings(ctx, api, originIDs, settings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(originIDsFingerprint(matched))
+	data.OriginIds = matchedSet
+	data.Devices = devices
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SWGDeviceSettingsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SWGDeviceSettingsResourceModel
+	var statedata *SWGDeviceSettingsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &statedata)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(statedata.OrgId).withContext(ctx)
+
+	// State was refreshed before planning, so it only holds the devices that
+	// already have the planned override.
+	var current, want []int64
+	resp.Diagnostics.Append(statedata.OriginIds.ElementsAs(ctx, &current, false)...)
+	resp.Diagnostics.Append(data.OriginIds.ElementsAs(ctx, &want, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	add, remove := diffOrigins(current, want)
+	if data.Enabled.ValueBool() != statedata.Enabled.ValueBool() {
+		add = want
+	}
+
+	// owned tracks the devices with an override the resource set, so that the
+	// state stays accurate when some devices or batches fail. A device whose
+	// override could not be changed stays in it, so that destroy still clears
+	// it.
+	owned := map[int64]bool{}
+	for _, originID := range current {
+		owned[originID] = true
+	}
+
+	results, err := api.RemoveDeviceSetting(swgEnabledSetting, remove, nil)
+	addDeviceSettingFailures(&resp.Diagnostics, "Error Clearing Umbrella SWG Device Settings", results)
+	for originID := range deviceSettingChanged(results) {
+		delete(owned, originID)
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Clearing Umbrella SWG Device Settings", fmt.Sprintf("Could not clear the SWG setting of %d devices", len(remove)), err, nil)
+	} else {
+		results, err = api.SetDeviceSetting(swgEnabledSetting, swgSettingValue(data.Enabled.ValueBool()), add, nil)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Error Setting Umbrella SWG Device Settings", fmt.Sprintf("Could not override the SWG setting of %d devices", len(add)), err, nil)
+		}
+		addDeviceSettingFailures(&resp.Diagnostics, "Error Setting Umbrella SWG Device Settings", results)
+		for originID := range deviceSettingChanged(results) {
+			owned[originID] = true
+		}
+	}
+
+	var applied []int64
+	for originID := range owned {
+		applied = append(applied, originID)
+	}
+
+	resp.Diagnostics.Append(setSWGDeviceSettingsModel(ctx, api, data, applied)...)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SWGDeviceSettingsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SWGDeviceSettingsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var originIDs []int64
+	resp.Diagnostics.Append(data.OriginIds.ElementsAs(ctx, &originIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	results, err := r.client.forOrg(data.OrgId).withContext(ctx).RemoveDeviceSetting(swgEnabledSetting, originIDs, nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Clearing Umbrella SWG Device Settings", fmt.Sprintf("Could not clear the SWG setting of %d devices", len(originIDs)), err, nil)
+		return
+	}
+
+	// A device that was deleted meanwhile has no override left to clear.
+	for _, result := range results {
+		if result.Code == http.StatusNotFound {
+			tflog.Warn(ctx, "Umbrella roaming computer is already gone", map[string]interface{}{
+				"origin_id": result.OriginId,
+			})
+		}
+	}
+	addDeviceSettingFailures(&resp.Diagnostics, "Error Clearing Umbrella SWG Device Settings", results, http.StatusNotFound)
+}
+
+// swgSettingValue is the SWGEnabled setting value for enabled.
+func swgSettingValue(enabled bool) string {
+	if enabled {
+		return "1"
+	}
+	return "0"
+}
+
+// setSWGDeviceSettingsModel stores originIDs, the devices whose override
+// the resource owns, in data together with their current override and status.
+func setSWGDeviceSettingsModel(ctx context.Context, api *apiClient, data *SWGDeviceSettingsResourceModel, originIDs []int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if originIDs == nil {
+		originIDs = []int64{}
+	}
+	originIDsSet, d := types.SetValueFrom(ctx, types.Int64Type, originIDs)
+	diags.Append(d...)
+
+	devices, d := swgDevices(ctx, api, originIDs)
+	diags.Append(d...)
+
+	data.Id = types.StringValue(originIDsFingerprint(originIDs))
+	data.OriginIds = originIDsSet
+	data.Devices = devices
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	return diags
+}
+
+// deviceSettingChanged returns the origin IDs of the devices whose setting
+// changed according to results.
+func deviceSettingChanged(results []DeviceSettingResult) map[int64]bool {
+	changed := map[int64]bool{}
+	for _, result := range results {
+		if result.Code >= 200 && result.Code < 300 {
+			changed[result.OriginId] = true
+		}
+	}
+	return changed
+}
+
+// addDeviceSettingFailures adds an error listing the devices whose setting
+// did not change, other than those failing with an ignored code.
+func addDeviceSettingFailures(diags *diag.Diagnostics, summary string, results []DeviceSettingResult, ignore ...int) {
+	var failures []string
+	for _, result := range results {
+		if result.Code >= 200 && result.Code < 300 {
+			continue
+		}
+		ignored := false
+		for _, code := range ignore {
+			ignored = ignored || result.Code == code
+		}
+		if !ignored {
+			failures = append(failures, fmt.Sprintf("origin ID %d: %s (%d)", result.OriginId, result.Message, result.Code))
+		}
+	}
+	if len(failures) == 0 {
+		return
+	}
+
+	detail := fmt.Sprintf("The setting of %d devices did not change:\n", len(failures))
+	if len(failures) > maxReportedDeviceFailures {
+		detail += strings.Join(failures[:maxReportedDeviceFailures], "\n") + fmt.Sprintf("\n... and %d more", len(failures)-maxReportedDeviceFailures)
+	} else {
+		detail += strings.Join(failures, "\n")
+	}
+	diags.AddAttributeError(path.Root("origin_ids"), summary, detail)
+}
+
+// swgDevices reads the override and status of the devices with originIDs.
+func swgDevices(ctx context.Context, api *apiClient, originIDs []int64) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	settings, err := api.ListDeviceSettings(swgEnabledSetting, originIDs, nil)
+	if err != nil {
+		addAPIError(&diags, "Error Reading Umbrella SWG Device Settings", fmt.Sprintf("Could not read the SWG setting of %d devices", len(originIDs)), err, nil)
+		return types.ListNull(types.ObjectType{AttrTypes: SWGDeviceModel{}.attrTypes()}), diags
+	}
+
+	return swgDevicesFromSettings(ctx, api, originIDs, settings)
+}
+
+// swgDevicesFromSettings combines settings with the SWG status the roaming
+// computers with originIDs report.
+func swgDevicesFromSettings(ctx context.Context, api *apiClient, originIDs []int64, settings []DeviceSetting) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	elemType := types.ObjectType{AttrTypes: SWGDeviceModel{}.attrTypes()}
+
+	computers, err := api.GetRoamingComputers(nil)
+	if err != nil {
+		addAPIError(&diags, "Error Reading Umbrella SWG Device Settings", "Could not list Umbrella Roaming Computers", err, nil)
+		return types.ListNull(elemType), diags
+	}
+	statuses := map[int64]string{}
+	for _, computer := range computers {
+		statuses[computer.OriginId] = computer.SwgStatus
+	}
+	overrides := map[int64]DeviceSetting{}
+	for _, setting := range settings {
+		overrides[setting.OriginId] = setting
+	}
+
+	sorted := append([]int64{}, originIDs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	devices := make([]SWGDeviceModel, 0, len(sorted))
+	for _, originID := range sorted {
+		device := SWGDeviceModel{
+			OriginId:   types.Int64Value(originID),
+			Enabled:    types.BoolNull(),
+			ModifiedAt: types.StringNull(),
+			SwgStatus:  types.StringNull(),
+		}
+		if setting, ok := overrides[originID]; ok {
+			device.Enabled = types.BoolValue(setting.Value == swgSettingValue(true))
+			device.ModifiedAt = types.StringValue(setting.ModifiedAt)
+		}
+		if status, ok := statuses[originID]; ok {
+			device.SwgStatus = types.StringValue(status)
+		}
+		devices = append(devices, device)
+	}
+
+	list, d := types.ListValueFrom(ctx, elemType, devices)
+	diags.Append(d...)
+	return list, diags
+}
+
+// originIDsFingerprint identifies a set of devices by a hash of their sorted
+// origin IDs.
+func originIDsFingerprint(originIDs []int64) string {
+	sorted := append([]int64{}, originIDs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	h := sha256.New()
+	for _, originID := range sorted {
+		h.Write([]byte(strconv.FormatInt(originID, 10) + ","))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
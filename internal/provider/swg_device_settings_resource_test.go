@@ -0,0 +1,195 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSWGDeviceSettingsResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var originIDs []int64
+	for i := 0; i < 4; i++ {
+		computer := fake.addRoamingComputer(0, RoamingComputer{Name: fmt.Sprintf("LAPTOP-%04d", i), SwgStatus: "Protected"})
+		originIDs = append(originIDs, computer.OriginId)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccSWGDeviceSettingsResourceConfig(fake, originIDs[:3], false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_swg_device_settings.test", "devices.#", "3"),
+					resource.TestCheckResourceAttr("umbrella_swg_device_settings.test", "devices.0.origin_id", fmt.Sprint(originIDs[0])),
+					resource.TestCheckResourceAttr("umbrella_swg_device_settings.test", "devices.0.enabled", "false"),
+					resource.TestCheckResourceAttr("umbrella_swg_device_settings.test", "devices.0.swg_status", "Protected"),
+					resource.TestCheckResourceAttrSet("umbrella_swg_device_settings.test", "devices.0.modified_at"),
+					testAccCheckSWGSettings(fake, originIDs, "0", "0", "0", ""),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: testAccSWGDeviceSettingsResourceConfig(fake, originIDs[1:], true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_swg_device_settings.test", "devices.#", "3"),
+					resource.TestCheckResourceAttr("umbrella_swg_device_settings.test", "devices.2.enabled", "true"),
+					testAccCheckSWGSettings(fake, originIDs, "", "1", "1", "1"),
+				),
+			},
+			// Overrides changed or cleared outside Terraform are set again
+			{
+				PreConfig: func() {
+					fake.setSWGSetting(originIDs[1], "")
+					fake.setSWGSetting(originIDs[2], "0")
+				},
+				Config: testAccSWGDeviceSettingsResourceConfig(fake, originIDs[1:], true),
+				Check:  testAccCheckSWGSettings(fake, originIDs, "", "1", "1", "1"),
+			},
+			// Per-device failures are reported
+			{
+				Config:      testAccSWGDeviceSettingsResourceConfig(fake, append([]int64{424242}, originIDs[1:]...), true),
+				ExpectError: regexp.MustCompile(`origin ID 424242: device not found`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+		CheckDestroy: testAccCheckSWGSettings(fake, originIDs, "", "", "", ""),
+	})
+}
+
+func TestAccSWGDeviceSettingsResource_partialFailure(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var originIDs []int64
+	for i := 0; i < 4; i++ {
+		computer := fake.addRoamingComputer(0, RoamingComputer{Name: fmt.Sprintf("LAPTOP-%04d", i)})
+		originIDs = append(originIDs, computer.OriginId)
+	}
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The devices that did change are kept in state, and cleared when
+			// the tainted resource is replaced.
+			{
+				Config:      testAccSWGDeviceSettingsResourceConfig(fake, []int64{originIDs[0], originIDs[1], 424242}, true),
+				ExpectError: regexp.MustCompile(`origin ID 424242: device not found`),
+			},
+			{
+				Config: testAccSWGDeviceSettingsResourceConfig(fake, []int64{originIDs[0], originIDs[2]}, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckSWGSettings(fake, originIDs, "1", "", "1", ""),
+					testAccCaptureAttr("umbrella_swg_device_settings.test", "id", &id),
+				),
+			},
+			{
+				Config:      testAccSWGDeviceSettingsResourceConfig(fake, []int64{originIDs[0], originIDs[3], 424242}, true),
+				ExpectError: regexp.MustCompile(`origin ID 424242: device not found`),
+			},
+			// The device added by the failed update is in state, so dropping
+			// it clears its override.
+			{
+				Config: testAccSWGDeviceSettingsResourceConfig(fake, []int64{originIDs[0]}, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckSWGSettings(fake, originIDs, "1", "", "", ""),
+					resource.TestCheckResourceAttr("umbrella_swg_device_settings.test", "id", originIDsFingerprint(originIDs[:1])),
+					func(*terraform.State) error {
+						if id == originIDsFingerprint(originIDs[:1]) {
+							return fmt.Errorf("expected the id to change with origin_ids")
+						}
+						return nil
+					},
+				),
+			},
+		},
+		CheckDestroy: testAccCheckSWGSettings(fake, originIDs, "", "", "", ""),
+	})
+}
+
+func TestAccSWGDeviceSettingsResource_batchFailure(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	originIDs := fake.addRoamingComputers(0, deviceSettingBatchSize+2)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The devices of the batch that went through are kept in state
+			// when the second batch fails.
+			{
+				PreConfig: func() {
+					fake.failAfter("POST", "/deployments/v2/deviceSettings/SWGEnabled/set", http.StatusInternalServerError, 1, 1)
+				},
+				Config:      testAccSWGDeviceSettingsResourceConfig(fake, originIDs, true),
+				ExpectError: regexp.MustCompile(`Could not override the SWG setting of 502 devices`),
+			},
+			{
+				Config: testAccSWGDeviceSettingsResourceConfig(fake, originIDs[:1], true),
+				Check:  testAccCheckSWGSettingCount(fake, originIDs, 1),
+			},
+			{
+				PreConfig: func() {
+					fake.failAfter("POST", "/deployments/v2/deviceSettings/SWGEnabled/set", http.StatusInternalServerError, 1, 1)
+				},
+				Config:      testAccSWGDeviceSettingsResourceConfig(fake, originIDs, true),
+				ExpectError: regexp.MustCompile(`Could not override the SWG setting of 501 devices`),
+			},
+			{
+				Config: testAccSWGDeviceSettingsResourceConfig(fake, originIDs[:1], true),
+				Check:  testAccCheckSWGSettingCount(fake, originIDs, 1),
+			},
+		},
+		CheckDestroy: testAccCheckSWGSettingCount(fake, originIDs, 0),
+	})
+}
+
+// testAccCheckSWGSettingCount checks that count of the devices in originIDs
+// have an SWGEnabled override.
+func testAccCheckSWGSettingCount(fake *fakeUmbrella, originIDs []int64, count int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		got := 0
+		for _, originID := range originIDs {
+			if fake.swgSetting(originID) != "" {
+				got++
+			}
+		}
+		if got != count {
+			return fmt.Errorf("expected %d SWG setting overrides, got %d", count, got)
+		}
+		return nil
+	}
+}
+
+// testAccCheckSWGSettings checks the SWGEnabled override of every device in
+// originIDs, "" meaning no override.
+func testAccCheckSWGSettings(fake *fakeUmbrella, originIDs []int64, values ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		for i, originID := range originIDs {
+			if got := fake.swgSetting(originID); got != values[i] {
+				return fmt.Errorf("expected SWG setting %q on origin ID %d, got %q", values[i], originID, got)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccSWGDeviceSettingsResourceConfig(fake *fakeUmbrella, originIDs []int64, enabled bool) string {
+	ids := make([]string, len(originIDs))
+	for i, id := range originIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_swg_device_settings" "test" {
+  origin_ids = [%s]
+  enabled    = %t
+}
+`, strings.Join(ids, ", "), enabled)
+}
@@ -0,0 +1,342 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TagDevicesResource{}
+var _ resource.ResourceWithImportState = &TagDevicesResource{}
+
+func NewTagDevicesResource() resource.Resource {
+	return &TagDevicesResource{}
+}
+
+// TagDevicesResource defines the resource implementation. It owns the whole
+// membership of a tag, so there should be one per tag.
+type TagDevicesResource struct {
+	client *apiClient
+}
+
+// TagDevicesResourceModel describes the resource data model.
+type TagDevicesResourceModel struct {
+	Id          types.Int64  `tfsdk:"id"`
+	TagId       types.Int64  `tfsdk:"tag_id"`
+	OriginIds   types.Set    `tfsdk:"origin_ids"`
+	LastUpdated types.String `tfsdk:"last_updated"`
+	OrgId       types.Int64  `tfsdk:"org_id"`
+}
+
+func (r *TagDevicesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tag_devices"
+}
+
+func (r *TagDevicesResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: fmt.Sprintf("Tag devices resource. Sets exactly which roaming computers have a tag, adding and removing devices in batches of %d. Devices tagged outside Terraform are untagged", tagDeviceBatchSize),
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"tag_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the tag",
+				Required:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"origin_ids": schema.SetAttribute{
+				MarkdownDescription: "The origin IDs of the roaming computers that have the tag",
+				ElementType:         types.Int64Type,
+				Required:            true,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the tag. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+	}
+}
+
+func (r *TagDevicesResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *TagDevicesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *TagDevicesResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+	tagID := data.TagId.ValueInt64()
+
+	current, found, err := tagOrigins(api, tagID)
+	if err == nil && !found {
+		resp.Diagnostics.AddAttributeError(path.Root("tag_id"), "Tag Not Found", fmt.Sprintf("No Umbrella Tag has ID %d.", tagID))
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Tag Devices", "Could not read the devices of Umbrella Tag ID "+strconv.FormatInt(tagID, 10), err, nil)
+		return
+	}
+
+	var want []int64
+	resp.Diagnostics.Append(data.OriginIds.ElementsAs(ctx, &want, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, api, tagID, current, want)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.Int64Value(tagID)
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	data.OrgId = api.orgIDValue()
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TagDevicesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *TagDevicesResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+	tagID := data.TagId.ValueInt64()
+
+	current, found, err := tagOrigins(api, tagID)
+	if err == nil && !found {
+		tflog.Warn(ctx, "Umbrella Tag no longer exists, removing its devices from state", map[string]interface{}{
+			"tag_id": tagID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Tag Devices", "Could not read the devices of Umbrella Tag ID "+strconv.FormatInt(tagID, 10), err, nil)
+		return
+	}
+
+	originIDs, diags := types.SetValueFrom(ctx, types.Int64Type, current)
+	resp.Diagnostics.Append(diags...)
+	data.Id = types.Int64Value(tagID)
+	data.OriginIds = originIDs
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TagDevicesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *TagDevicesResourceModel
+	var statedata *TagDevicesResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &statedata)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(statedata.OrgId).withContext(ctx)
+
+	// State was refreshed before planning, so it holds the current devices.
+	var current, want []int64
+	resp.Diagnostics.Append(statedata.OriginIds.ElementsAs(ctx, &current, false)...)
+	resp.Diagnostics.Append(data.OriginIds.ElementsAs(ctx, &want, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, api, statedata.TagId.ValueInt64(), current, want)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// apply adds the devices in want but not in current to the tag, and removes
+// the devices in current but not in want.
+func (r *TagDevicesResource) apply(ctx context.Context, api *apiClient, tagID int64, current, want []int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	add, remove := diffOrigins(current, want)
+	tflog.Debug(ctx, "Changing Umbrella tag devices", map[string]interface{}{
+		"tag_id": tagID,
+		"add":    len(add),
+		"remove": len(remove),
+	})
+
+	if err := api.RemoveTagDevices(tagID, remove, nil); err != nil {
+		addAPIError(&diags, "Error Updating Umbrella Tag Devices", fmt.Sprintf("Could not remove %d devices from Umbrella Tag ID %d", len(remove), tagID), err, nil)
+		return diags
+	}
+	if err := api.AddTagDevices(tagID, add, nil); err != nil {
+		addAPIError(&diags, "Error Updating Umbrella Tag Devices", fmt.Sprintf("Could not add %d devices to Umbrella Tag ID %d", len(add), tagID), err, apiErrorPaths{http.StatusBadRequest: path.Root("origin_ids")})
+		return diags
+	}
+
+	return diags
+}
+
+func (r *TagDevicesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *TagDevicesResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var current []int64
+	resp.Diagnostics.Append(data.OriginIds.ElementsAs(ctx, &current, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.forOrg(data.OrgId).withContext(ctx).RemoveTagDevices(data.TagId.ValueInt64(), current, nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Tag is already gone", map[string]interface{}{
+			"tag_id": data.TagId.ValueInt64(),
+		})
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Tag Devices", "Could not remove the devices from Umbrella Tag ID "+strconv.FormatInt(data.TagId.ValueInt64(), 10), err, nil)
+		return
+	}
+}
+
+func (r *TagDevicesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	orgid, tagid, diags := resolveImportID(r.client, req.ID, "Tag", tagsNamed)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tag_id"), tagid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
+}
+
+// tagOrigins returns the sorted origin IDs of the roaming computers with the
+// tag tagID, and whether the tag exists.
+func tagOrigins(api *apiClient, tagID int64) ([]int64, bool, error) {
+	tags, err := api.GetTags(nil)
+	if err != nil {
+		return nil, false, err
+	}
+	found := false
+	for _, tag := range tags {
+		if tag.Id == tagID {
+			found = true
+		}
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	computers, err := api.GetRoamingComputers(nil)
+	if err != nil {
+		return nil, true, err
+	}
+	origins := []int64{}
+	for _, computer := range computers {
+		for _, tag := range computer.Tags {
+			if tag.Id == tagID {
+				origins = append(origins, computer.OriginId)
+			}
+		}
+	}
+	sort.Slice(origins, func(i, j int) bool { return origins[i] < origins[j] })
+
+	return origins, true, nil
+}
+
+// diffOrigins returns the origin IDs in want but not in current, and those in
+// current but not in want, both sorted.
+func diffOrigins(current, want []int64) (add, remove []int64) {
+	has := map[int64]bool{}
+	for _, id := range current {
+		has[id] = true
+	}
+	wanted := map[int64]bool{}
+	for _, id := range want {
+		wanted[id] = true
+		if !has[id] {
+			add = append(add, id)
+		}
+	}
+	for _, id := range current {
+		if !wanted[id] {
+			remove = append(remove, id)
+		}
+	}
+	sort.Slice(add, func(i, j int) bool { return add[i] < add[j] })
+	sort.Slice(remove, func(i, j int) bool { return remove[i] < remove[j] })
+
+	return add, remove
+}
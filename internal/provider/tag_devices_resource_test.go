@@ -0,0 +1,138 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTagDevicesResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	originIDs := fake.addRoamingComputers(0, 5)
+	var tagID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccTagDevicesResourceConfig(fake, originIDs[:3]),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_tag_devices.test", "origin_ids.#", "3"),
+					resource.TestCheckResourceAttrPair("umbrella_tag_devices.test", "id", "umbrella_tag.test", "id"),
+					testAccCaptureAttr("umbrella_tag.test", "id", &tagID),
+					testAccCheckTagged(fake, &tagID, 3),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "umbrella_tag_devices.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccTagDevicesResourceConfig(fake, originIDs[2:]),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_tag_devices.test", "origin_ids.#", "3"),
+					resource.TestCheckTypeSetElemAttr("umbrella_tag_devices.test", "origin_ids.*", fmt.Sprint(originIDs[4])),
+					testAccCheckTagged(fake, &tagID, 3),
+				),
+			},
+			// A device tagged outside Terraform is untagged again
+			{
+				PreConfig: func() {
+					api, err := newAPIClient(context.Background(), fake.server.URL, "fake-key", "fake-secret", 0, defaultRetryPolicy())
+					if err != nil {
+						t.Fatal(err)
+					}
+					id, _ := strconv.ParseInt(tagID, 10, 64)
+					if err := api.AddTagDevices(id, originIDs[:1], nil); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccTagDevicesResourceConfig(fake, originIDs[2:]),
+				Check:  testAccCheckTagged(fake, &tagID, 3),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestTagDevicesAreBatched(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	originIDs := fake.addRoamingComputers(0, 2*tagDeviceBatchSize+1)
+	tag := fake.addTag(0, "laptops")
+
+	client, err := newAPIClient(context.Background(), fake.server.URL, "fake-key", "fake-secret", 0, defaultRetryPolicy())
+	if err != nil {
+		t.Fatalf("newAPIClient: %s", err)
+	}
+
+	if err := client.AddTagDevices(tag.Id, originIDs, nil); err != nil {
+		t.Fatalf("AddTagDevices: %s", err)
+	}
+	if fake.tagBatches != 3 {
+		t.Errorf("expected 3 add batches, got %d", fake.tagBatches)
+	}
+
+	current, found, err := tagOrigins(client, tag.Id)
+	if err != nil || !found {
+		t.Fatalf("tagOrigins: %v, %v", found, err)
+	}
+	if len(current) != len(originIDs) {
+		t.Fatalf("expected %d tagged devices, got %d", len(originIDs), len(current))
+	}
+
+	if err := client.RemoveTagDevices(tag.Id, originIDs[1:], nil); err != nil {
+		t.Fatalf("RemoveTagDevices: %s", err)
+	}
+	if fake.tagBatches != 5 {
+		t.Errorf("expected 2 remove batches, got %d", fake.tagBatches-3)
+	}
+	if n := fake.taggedCount(tag.Id); n != 1 {
+		t.Errorf("expected 1 tagged device, got %d", n)
+	}
+}
+
+func TestDiffOrigins(t *testing.T) {
+	add, remove := diffOrigins([]int64{3, 1, 2}, []int64{4, 2, 5})
+	if fmt.Sprint(add) != "[4 5]" || fmt.Sprint(remove) != "[1 3]" {
+		t.Errorf("diffOrigins = %v, %v, want [4 5], [1 3]", add, remove)
+	}
+}
+
+// testAccCheckTagged checks that the tag with *tagID has n devices.
+func testAccCheckTagged(fake *fakeUmbrella, tagID *string, n int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		id, _ := strconv.ParseInt(*tagID, 10, 64)
+		if got := fake.taggedCount(id); got != n {
+			return fmt.Errorf("expected %d tagged devices, got %d", n, got)
+		}
+		return nil
+	}
+}
+
+func testAccTagDevicesResourceConfig(fake *fakeUmbrella, originIDs []int64) string {
+	ids := make([]string, len(originIDs))
+	for i, id := range originIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_tag" "test" {
+  name = "laptops"
+}
+
+resource "umbrella_tag_devices" "test" {
+  tag_id     = umbrella_tag.test.id
+  origin_ids = [%s]
+}
+`, strings.Join(ids, ", "))
+}
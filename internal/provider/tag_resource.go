@@ -0,0 +1,244 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TagResource{}
+var _ resource.ResourceWithImportState = &TagResource{}
+
+func NewTagResource() resource.Resource {
+	return &TagResource{}
+}
+
+// TagResource defines the resource implementation.
+type TagResource struct {
+	client *apiClient
+}
+
+// TagResourceModel describes the resource data model.
+type TagResourceModel struct {
+	Id          types.Int64  `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+	LastUpdated types.String `tfsdk:"last_updated"`
+	OrgId       types.Int64  `tfsdk:"org_id"`
+}
+
+func (r *TagResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tag"
+}
+
+func (r *TagResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Tag resource. Tags group roaming computers so that policies can select them. Use `umbrella_tag_devices` to assign devices to a tag",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the tag",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the tag. Tags cannot be renamed, so changing it replaces the tag",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the tag was created",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the tag. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+		},
+	}
+}
+
+func (r *TagResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *TagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *TagResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	tag, err := api.CreateTag(data.Name.ValueString(), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Creating Umbrella Tag", "Could not create Umbrella Tag", err, apiErrorPaths{http.StatusConflict: path.Root("name")})
+		return
+	}
+
+	setTagModel(data, tag)
+	data.OrgId = api.orgIDValue()
+
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TagResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *TagResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	// The API has no endpoint for a single tag.
+	tags, err := api.GetTags(nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Tag", "Could not read Umbrella Tag ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, nil)
+		return
+	}
+
+	var tag *Tag
+	for i := range tags {
+		if tags[i].Id == data.Id.ValueInt64() {
+			tag = &tags[i]
+		}
+	}
+	if tag == nil {
+		tflog.Warn(ctx, "Umbrella Tag no longer exists, removing it from state", map[string]interface{}{
+			"id": data.Id.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	setTagModel(data, tag)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only stores the plan, as every attribute that can change replaces
+// the tag.
+func (r *TagResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *TagResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TagResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *TagResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.forOrg(data.OrgId).withContext(ctx).DeleteTag(data.Id.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Tag is already gone", map[string]interface{}{
+			"id": data.Id.ValueInt64(),
+		})
+		return
+	}
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Tag", "Could not delete Umbrella Tag ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, nil)
+		return
+	}
+}
+
+func (r *TagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	orgid, tagid, diags := resolveImportID(r.client, req.ID, "Tag", tagsNamed)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), tagid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
+}
+
+// tagsNamed returns the IDs of the tags called name.
+func tagsNamed(api *apiClient, name string) ([]int64, error) {
+	tags, err := api.GetTags(nil)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for _, tag := range tags {
+		if tag.Name == name {
+			ids = append(ids, tag.Id)
+		}
+	}
+	return ids, nil
+}
+
+func setTagModel(data *TagResourceModel, tag *Tag) {
+	data.Id = types.Int64Value(tag.Id)
+	data.Name = types.StringValue(tag.Name)
+	data.CreatedAt = types.StringValue(tag.CreatedAt)
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+}
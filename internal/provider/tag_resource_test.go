@@ -0,0 +1,82 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTagResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var tagID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccTagResourceConfig(fake, "finance"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_tag.test", "name", "finance"),
+					resource.TestCheckResourceAttrSet("umbrella_tag.test", "created_at"),
+					testAccCaptureAttr("umbrella_tag.test", "id", &tagID),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "umbrella_tag.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			{
+				ResourceName:            "umbrella_tag.test",
+				ImportState:             true,
+				ImportStateId:           "name:finance",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			// Renaming replaces the tag
+			{
+				Config: testAccTagResourceConfig(fake, "accounting"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_tag.test", "name", "accounting"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["umbrella_tag.test"].Primary.Attributes["id"]; id == tagID {
+							return fmt.Errorf("expected a new tag, id is still %s", id)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccTagResourceConfig(fake, "accounting") + `
+resource "umbrella_tag" "duplicate" {
+  name       = "accounting"
+  depends_on = [umbrella_tag.test]
+}
+`,
+				ExpectError: regexp.MustCompile(`already exists`),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			if n := fake.tagCount(); n != 0 {
+				return fmt.Errorf("%d tags left behind", n)
+			}
+			return nil
+		},
+	})
+}
+
+func testAccTagResourceConfig(fake *fakeUmbrella, name string) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_tag" "test" {
+  name = %q
+}
+`, name)
+}
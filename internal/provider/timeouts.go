@@ -0,0 +1,42 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// defaultOperationTimeout applies to operations without a configured timeout.
+const defaultOperationTimeout = 10 * time.Minute
+
+// deadlineExceeded reports whether the operation deadline set on ctx from the
+// resource timeouts has passed. Timeouts of single HTTP attempts do not count.
+func deadlineExceeded(ctx context.Context) bool {
+	return ctx.Err() == context.DeadlineExceeded
+}
+
+// addTimeoutError reports that operation ("create", "read", "update" or
+// "delete") on the resource with id did not finish within timeout. id is
+// empty while the resource is being created.
+func addTimeoutError(diags *diag.Diagnostics, operation, resourceType, id string, timeout time.Duration) {
+	subject := resourceType
+	if id != "" {
+		subject += " ID " + id
+	}
+
+	diags.AddError(
+		fmt.Sprintf("Timed Out %s %s", timeoutVerbs[operation], resourceType),
+		fmt.Sprintf("%s %s did not finish within the %s timeout of %s. "+
+			"The operation may still complete on Umbrella's side. Increase timeouts.%s if it regularly needs longer.",
+			timeoutVerbs[operation], subject, operation, timeout, operation),
+	)
+}
+
+var timeoutVerbs = map[string]string{
+	"create": "Creating",
+	"read":   "Reading",
+	"update": "Updating",
+	"delete": "Deleting",
+}
@@ -0,0 +1,165 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TunnelCredentialsResource{}
+var _ resource.ResourceWithValidateConfig = &TunnelCredentialsResource{}
+
+func NewTunnelCredentialsResource() resource.Resource {
+	return &TunnelCredentialsResource{}
+}
+
+// TunnelCredentialsResource generates a tunnel pre-shared key. It never calls
+// the API; the secret is handed to umbrella_tunnel, which registers it.
+type TunnelCredentialsResource struct{}
+
+// TunnelCredentialsResourceModel describes the resource data model.
+type TunnelCredentialsResourceModel struct {
+	Id      types.String `tfsdk:"id"`
+	Secret  types.String `tfsdk:"secret"`
+	Length  types.Int64  `tfsdk:"length"`
+	Keepers types.Map    `tfsdk:"keepers"`
+}
+
+func (r *TunnelCredentialsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tunnel_credentials"
+}
+
+func (r *TunnelCredentialsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Generates a tunnel pre-shared key that meets Umbrella's complexity rules, for `client.authentication.parameters.secret` of `umbrella_tunnel`. The secret only lives in the Terraform state",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "A fingerprint of the secret that is safe to show in logs. It changes whenever the secret does",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secret": schema.StringAttribute{
+				MarkdownDescription: "The generated pre-shared key",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"length": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The length of the secret, between %d and %d. Defaults to %d", tunnelSecretMinLength, tunnelSecretMaxLength, generatedTunnelSecretLength),
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"keepers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that generate a new secret when they change",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *TunnelCredentialsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data TunnelCredentialsResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Length.IsNull() || data.Length.IsUnknown() {
+		return
+	}
+
+	if length := data.Length.ValueInt64(); length < tunnelSecretMinLength || length > tunnelSecretMaxLength {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("length"),
+			"Invalid Tunnel Secret Length",
+			fmt.Sprintf("Umbrella requires pre-shared keys of %d to %d characters, got %d", tunnelSecretMinLength, tunnelSecretMaxLength, length),
+		)
+	}
+}
+
+func (r *TunnelCredentialsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *TunnelCredentialsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Length.IsNull() || data.Length.IsUnknown() {
+		data.Length = types.Int64Value(generatedTunnelSecretLength)
+	}
+
+	secret, err := generateTunnelSecret(int(data.Length.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Generating Umbrella Tunnel Secret",
+			"Could not generate tunnel secret, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	data.Secret = types.StringValue(secret)
+	data.Id = types.StringValue(tunnelSecretFingerprint(secret))
+
+	tflog.Trace(ctx, "generated tunnel credentials", map[string]interface{}{
+		"id": data.Id.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read keeps the state as is, the secret only exists in Terraform.
+func (r *TunnelCredentialsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+}
+
+// Update is never called with a change, since every configurable attribute
+// requires replacement.
+func (r *TunnelCredentialsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *TunnelCredentialsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete only removes the secret from state. Credentials that umbrella_tunnel
+// registered with it are removed when the tunnel rotates to a new secret.
+func (r *TunnelCredentialsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// tunnelSecretFingerprint identifies secret without revealing it.
+func tunnelSecretFingerprint(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:8])
+}
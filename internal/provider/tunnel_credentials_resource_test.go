@@ -0,0 +1,101 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTunnelCredentialsResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var tunnelID, secret string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTunnelCredentialsResourceConfig(fake, "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_tunnel_credentials.test", "length", "32"),
+					resource.TestCheckResourceAttrSet("umbrella_tunnel_credentials.test", "id"),
+					resource.TestCheckResourceAttrPair("umbrella_tunnel.test", "client.authentication.parameters.secret", "umbrella_tunnel_credentials.test", "secret"),
+					testAccCaptureAttr("umbrella_tunnel.test", "id", &tunnelID),
+					testAccCaptureAttr("umbrella_tunnel_credentials.test", "secret", &secret),
+					func(*terraform.State) error {
+						if err := validateTunnelSecret(secret); err != nil {
+							return fmt.Errorf("generated secret %s", err)
+						}
+						return testAccCheckTunnelSecrets(fake, &tunnelID, secret)(nil)
+					},
+				),
+			},
+			// Changing a keeper generates a new secret, which the tunnel rotates to.
+			{
+				Config: testAccTunnelCredentialsResourceConfig(fake, "2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("umbrella_tunnel.test", "client.authentication.parameters.secret", "umbrella_tunnel_credentials.test", "secret"),
+					testAccCheckAttrUnchanged("umbrella_tunnel.test", "id", &tunnelID),
+					func(s *terraform.State) error {
+						rotated := s.RootModule().Resources["umbrella_tunnel_credentials.test"].Primary.Attributes["secret"]
+						if rotated == secret {
+							return fmt.Errorf("secret was not regenerated")
+						}
+						return testAccCheckTunnelSecrets(fake, &tunnelID, rotated)(s)
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccTunnelCredentialsResource_invalidLength(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "umbrella_tunnel_credentials" "test" {
+  length = 8
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Tunnel Secret Length`),
+			},
+		},
+	})
+}
+
+func testAccTunnelCredentialsResourceConfig(fake *fakeUmbrella, keeper string) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_tunnel_credentials" "test" {
+  keepers = {
+    rotation = %[1]q
+  }
+}
+
+resource "umbrella_site" "test" {
+  name = "tunnel-site"
+}
+
+resource "umbrella_tunnel" "test" {
+  name           = "branch-1"
+  site_origin_id = umbrella_site.test.origin_id
+  network_cidrs  = ["10.10.0.0/24"]
+
+  client = {
+    device_type = "ASA"
+    authentication = {
+      type = "PSK"
+      parameters = {
+        id_prefix = "branch"
+        secret    = umbrella_tunnel_credentials.test.secret
+      }
+    }
+  }
+}
+`, keeper)
+}
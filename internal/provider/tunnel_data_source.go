@@ -3,10 +3,12 @@ package umbrellaprovider
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 
-	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/olegunza/umbrella-api-go/umbrella"
@@ -21,29 +23,36 @@ func NewTunnelDataSource() datasource.DataSource {
 }
 
 type TunnelDataSource struct {
-	client *umbrella.Client
+	client *apiClient
 }
+
+// TunnelDataSourceModel describes the data source data model.
 type TunnelDataSourceModel struct {
-	tunnels []VAModel `tfsdk:"vas"`
+	Id           types.Int64   `tfsdk:"id"`
+	Name         types.String  `tfsdk:"name"`
+	SiteOriginId types.Int64   `tfsdk:"site_origin_id"`
+	ServiceType  types.String  `tfsdk:"service_type"`
+	DeviceType   types.String  `tfsdk:"device_type"`
+	Tunnel       *TunnelModel  `tfsdk:"tunnel"`
+	Tunnels      []TunnelModel `tfsdk:"tunnels"`
 }
-type TunnelDataSourceModel struct {
-	Id           types.Int64  `tfsdk:"id"`
-	Uri          types.String `tfsdk:"uri"`
-	Name         types.String `tfsdk:"name"`
-	SiteOriginId types.Int64  `tfsdk:"site_origin_id"`
-	Client       types.Object `tfsdk:"client"`
-	Transport    types.Object `tfsdk:"transport"`
-	ServiceType  types.String `tfsdk:"service_type"`
-	NetworkCidrs types.List   `tfsdk:"network_cidrs"`
-	//Meta         *TunnelMetaResourceModel   `tfsdk:"meta"`
-	ModifiedAt  types.String `tfsdk:"modified_at"`
-	CreatedAt   types.String `tfsdk:"created_at"`
-	LastUpdated types.String `tfsdk:"last_updated"`
+
+type TunnelModel struct {
+	Id           types.Int64                 `tfsdk:"id"`
+	Uri          types.String                `tfsdk:"uri"`
+	Name         types.String                `tfsdk:"name"`
+	SiteOriginId types.Int64                 `tfsdk:"site_origin_id"`
+	Client       TunnelClientDataSourceModel `tfsdk:"client"`
+	Transport    TunnelTransDataSourceModel  `tfsdk:"transport"`
+	ServiceType  types.String                `tfsdk:"service_type"`
+	NetworkCidrs types.List                  `tfsdk:"network_cidrs"`
+	ModifiedAt   types.String                `tfsdk:"modified_at"`
+	CreatedAt    types.String                `tfsdk:"created_at"`
 }
 
 type TunnelClientDataSourceModel struct {
-	DeviceType     types.String `tfsdk:"device_type"`
-	Authentication types.Object `tfsdk:"authentication"`
+	DeviceType     types.String              `tfsdk:"device_type"`
+	Authentication TunnelAuthDataSourceModel `tfsdk:"authentication"`
 }
 
 type TunnelTransDataSourceModel struct {
@@ -51,15 +60,13 @@ type TunnelTransDataSourceModel struct {
 }
 
 type TunnelAuthDataSourceModel struct {
-	Type       types.String `tfsdk:"type"`
-	Parameters types.Object `tfsdk:"parameters"`
+	Type       types.String                    `tfsdk:"type"`
+	Parameters TunnelAuthParamsDataSourceModel `tfsdk:"parameters"`
 }
 
 type TunnelAuthParamsDataSourceModel struct {
 	Id         types.String `tfsdk:"id"`
 	ModifiedAt types.String `tfsdk:"modified_at"`
-	Secret     types.String `tfsdk:"secret"`
-	IdPrefix   types.String `tfsdk:"id_prefix"`
 }
 
 func (d *TunnelDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -69,51 +76,114 @@ func (d *TunnelDataSource) Metadata(ctx context.Context, req datasource.Metadata
 func (d *TunnelDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "DC list data source",
+		MarkdownDescription: "Tunnel data source. Lists network tunnels, optionally filtered. " +
+			"Setting `id` or `name` looks up a single tunnel and fails unless exactly one tunnel matches.",
 		Attributes: map[string]schema.Attribute{
-			"continents": schema.ListNestedAttribute{
-				Computed: true,
+			"id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the Tunnel to look up. Set to the ID of the Tunnel found by `name`",
+				Optional:            true,
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The exact name of the Tunnel to look up",
+				Optional:            true,
+			},
+			"site_origin_id": schema.Int64Attribute{
+				MarkdownDescription: "Only return Tunnels attached to the Site with this origin ID",
+				Optional:            true,
+			},
+			"service_type": schema.StringAttribute{
+				MarkdownDescription: "Only return Tunnels with this service type",
+				Optional:            true,
+			},
+			"device_type": schema.StringAttribute{
+				MarkdownDescription: "Only return Tunnels whose client has this device type",
+				Optional:            true,
+			},
+			"tunnel": schema.SingleNestedAttribute{
+				MarkdownDescription: "The Tunnel found by `id` or `name`",
+				Computed:            true,
+				Attributes:          tunnelDataSourceAttr(),
+			},
+			"tunnels": schema.ListNestedAttribute{
+				MarkdownDescription: "The Tunnels matching all filters",
+				Computed:            true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: continentsResourceAttr(),
+					Attributes: tunnelDataSourceAttr(),
 				},
 			},
 		},
 	}
 }
 
-func citiesResourceAttr() map[string]schema.Attribute {
+func tunnelDataSourceAttr() map[string]schema.Attribute {
 	return map[string]schema.Attribute{
-		"latitude": schema.StringAttribute{
-			Computed: true,
+		"id": schema.Int64Attribute{
+			MarkdownDescription: "The ID of the Tunnel",
+			Computed:            true,
 		},
-		"longitude": schema.StringAttribute{
-			Computed: true,
+		"uri": schema.StringAttribute{
+			MarkdownDescription: "The Uri of the Tunnel",
+			Computed:            true,
 		},
 		"name": schema.StringAttribute{
-			Computed: true,
+			MarkdownDescription: "The name of the Tunnel",
+			Computed:            true,
 		},
-		"dc": schema.StringAttribute{
-			Computed: true,
+		"site_origin_id": schema.Int64Attribute{
+			MarkdownDescription: "The origin ID of the Site",
+			Computed:            true,
 		},
-		"range": schema.StringAttribute{
+		"client": schema.SingleNestedAttribute{
 			Computed: true,
+			Attributes: map[string]schema.Attribute{
+				"device_type": schema.StringAttribute{
+					Computed: true,
+				},
+				"authentication": schema.SingleNestedAttribute{
+					Computed: true,
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Computed: true,
+						},
+						"parameters": schema.SingleNestedAttribute{
+							Computed: true,
+							Attributes: map[string]schema.Attribute{
+								"id": schema.StringAttribute{
+									Computed: true,
+								},
+								"modified_at": schema.StringAttribute{
+									Computed: true,
+								},
+							},
+						},
+					},
+				},
+			},
 		},
-		"fqdn": schema.StringAttribute{
+		"transport": schema.SingleNestedAttribute{
 			Computed: true,
+			Attributes: map[string]schema.Attribute{
+				"protocol": schema.StringAttribute{
+					Computed: true,
+				},
+			},
 		},
-	}
-}
-
-func continentsResourceAttr() map[string]schema.Attribute {
-	return map[string]schema.Attribute{
-		"name": schema.StringAttribute{
-			Computed: true,
+		"service_type": schema.StringAttribute{
+			MarkdownDescription: "The service type of the Tunnel",
+			Computed:            true,
 		},
-		"cities": schema.ListNestedAttribute{
-			Computed: true,
-			NestedObject: schema.NestedAttributeObject{
-				Attributes: citiesResourceAttr(),
-			},
+		"network_cidrs": schema.ListAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"modified_at": schema.StringAttribute{
+			MarkdownDescription: "The date and time (ISO8601 timestamp) when the Tunnel was modified",
+			Computed:            true,
+		},
+		"created_at": schema.StringAttribute{
+			MarkdownDescription: "The date and time (ISO8601 timestamp) when the Tunnel was created",
+			Computed:            true,
 		},
 	}
 }
@@ -124,12 +194,12 @@ func (d *TunnelDataSource) Configure(ctx context.Context, req datasource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(*umbrella.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -138,16 +208,53 @@ func (d *TunnelDataSource) Configure(ctx context.Context, req datasource.Configu
 	d.client = client
 }
 
-func typeFromAttrs(in map[string]schema.Attribute) map[string]attr.Type {
-	out := map[string]attr.Type{}
-	for k, v := range in {
-		out[k] = v.GetType()
+// matchTunnel reports whether tunnel passes every filter set in the data source configuration.
+func matchTunnel(data TunnelDataSourceModel, tunnel umbrella.NetworkTunnel) bool {
+	if !data.Name.IsNull() && tunnel.Name != data.Name.ValueString() {
+		return false
+	}
+	if !data.SiteOriginId.IsNull() && tunnel.SiteOriginId != data.SiteOriginId.ValueInt64() {
+		return false
+	}
+	if !data.ServiceType.IsNull() && tunnel.ServiceType != data.ServiceType.ValueString() {
+		return false
+	}
+	if !data.DeviceType.IsNull() && tunnel.Client.DeviceType != data.DeviceType.ValueString() {
+		return false
+	}
+	return true
+}
+
+func tunnelModelFrom(ctx context.Context, tunnel umbrella.NetworkTunnel) TunnelModel {
+	cidrs, _ := types.ListValueFrom(ctx, types.StringType, tunnel.NetworkCIDRs)
+
+	return TunnelModel{
+		Id:           types.Int64Value(tunnel.Id),
+		Uri:          types.StringValue(tunnel.Uri),
+		Name:         types.StringValue(tunnel.Name),
+		SiteOriginId: types.Int64Value(tunnel.SiteOriginId),
+		Client: TunnelClientDataSourceModel{
+			DeviceType: types.StringValue(tunnel.Client.DeviceType),
+			Authentication: TunnelAuthDataSourceModel{
+				Type: types.StringValue(tunnel.Client.Authentication.Type),
+				Parameters: TunnelAuthParamsDataSourceModel{
+					Id:         types.StringValue(tunnel.Client.Authentication.Parameters.Id),
+					ModifiedAt: types.StringValue(tunnel.Client.Authentication.Parameters.ModifiedAt),
+				},
+			},
+		},
+		Transport: TunnelTransDataSourceModel{
+			Protocol: types.StringValue(tunnel.Transport.Protocol),
+		},
+		ServiceType:  types.StringValue(tunnel.ServiceType),
+		NetworkCidrs: cidrs,
+		ModifiedAt:   types.StringValue(tunnel.ModifiedAt),
+		CreatedAt:    types.StringValue(tunnel.CreatedAt),
 	}
-	return out
 }
 
 func (d *TunnelDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var data DCListDataSourceModel
+	var data TunnelDataSourceModel
 
 	// Read Terraform configuration data into the model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -156,45 +263,55 @@ func (d *TunnelDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	dclist, err := d.client.GetDCs(&d.client.Token)
+	var tunnels []umbrella.NetworkTunnel
 
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Umbrella Dc List",
-			err.Error(),
-		)
-		return
+	if !data.Id.IsNull() {
+		tunnel, err := d.client.GetTunnel(data.Id.ValueInt64(), nil)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to Read Umbrella Tunnel", "Could not read Umbrella Tunnel ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, apiErrorPaths{http.StatusNotFound: path.Root("id")})
+			return
+		}
+		tunnels = append(tunnels, *tunnel)
+	} else {
+		var err error
+		tunnels, err = d.client.GetTunnels(nil)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to Read Umbrella Tunnels", "Could not list Umbrella Tunnels", err, nil)
+			return
+		}
 	}
 
-	var dccitieslist []City
-	var dccontlist []Continent
-
-	for _, continent := range dclist.Continents {
-		for _, city := range continent.Cities {
-			dccity := City{
-				Latitude:  types.StringValue(city.Latitude),
-				Longitude: types.StringValue(city.Longitude),
-				Name:      types.StringValue(city.Name),
-				Dc:        types.StringValue(city.Dc),
-				Range:     types.StringValue(city.Range),
-				Fqdn:      types.StringValue(city.Fqdn),
-			}
-
-			dccitieslist = append(dccitieslist, dccity)
-		}
-		cities, _ := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: typeFromAttrs(citiesResourceAttr())}, dccitieslist)
-		dccitieslist = nil
-		dccontinent := Continent{
-			Name:   types.StringValue(continent.Name),
-			Cities: cities,
+	data.Tunnels = []TunnelModel{}
+	for _, tunnel := range tunnels {
+		if matchTunnel(data, tunnel) {
+			data.Tunnels = append(data.Tunnels, tunnelModelFrom(ctx, tunnel))
 		}
+	}
 
-		dccontlist = append(dccontlist, dccontinent)
+	// A lookup by ID or name must resolve to exactly one tunnel.
+	if !data.Id.IsNull() || !data.Name.IsNull() {
+		switch len(data.Tunnels) {
+		case 0:
+			resp.Diagnostics.AddError(
+				"No Umbrella Tunnel Found",
+				"No tunnel matches the given id, name and filters.",
+			)
+			return
+		case 1:
+			data.Tunnel = &data.Tunnels[0]
+			data.Id = data.Tunnel.Id
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Multiple Umbrella Tunnels Found",
+				fmt.Sprintf("%d tunnels are named %q. Use id to select one of them.", len(data.Tunnels), data.Name.ValueString()),
+			)
+			return
+		}
 	}
-	continents, _ := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: typeFromAttrs(continentsResourceAttr())}, dccontlist)
-	data.Continents = continents
 
 	tflog.Trace(ctx, "read a data source")
 
+	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
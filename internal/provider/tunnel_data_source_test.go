@@ -0,0 +1,91 @@
+package umbrellaprovider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/olegunza/umbrella-api-go/umbrella"
+)
+
+func TestAccTunnelDataSource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTunnelResourceConfig(fake, "branch-1") + `
+data "umbrella_tunnel" "by_name" {
+  name = umbrella_tunnel.test.name
+}
+
+data "umbrella_tunnel" "by_id" {
+  id          = umbrella_tunnel.test.id
+  device_type = "ASA"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.umbrella_tunnel.by_name", "id", "umbrella_tunnel.test", "id"),
+					resource.TestCheckResourceAttrPair("data.umbrella_tunnel.by_name", "tunnel.id", "umbrella_tunnel.test", "id"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel.by_name", "tunnels.#", "1"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel.by_id", "tunnel.name", "branch-1"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel.by_id", "tunnel.client.device_type", "ASA"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTunnelDataSource_missing(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+data "umbrella_tunnel" "test" {
+  name = "does-not-exist"
+}
+`,
+				ExpectError: regexp.MustCompile(`No Umbrella Tunnel Found`),
+			},
+		},
+	})
+}
+
+func TestMatchTunnel(t *testing.T) {
+	tunnel := umbrella.NetworkTunnel{
+		Name:         "branch-1",
+		SiteOriginId: 42,
+		ServiceType:  "SIG",
+		Client:       umbrella.TunnelClient{DeviceType: "ASA"},
+	}
+	unfiltered := TunnelDataSourceModel{
+		Id:           types.Int64Null(),
+		Name:         types.StringNull(),
+		SiteOriginId: types.Int64Null(),
+		ServiceType:  types.StringNull(),
+		DeviceType:   types.StringNull(),
+	}
+
+	if !matchTunnel(unfiltered, tunnel) {
+		t.Errorf("expected a tunnel to match when no filter is set")
+	}
+
+	filtered := unfiltered
+	filtered.SiteOriginId = types.Int64Value(42)
+	filtered.DeviceType = types.StringValue("ASA")
+	if !matchTunnel(filtered, tunnel) {
+		t.Errorf("expected a tunnel to match site_origin_id and device_type")
+	}
+
+	filtered.ServiceType = types.StringValue("Private Access")
+	if matchTunnel(filtered, tunnel) {
+		t.Errorf("expected a tunnel with another service type not to match")
+	}
+}
@@ -3,9 +3,15 @@ package umbrellaprovider
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -14,6 +20,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -23,6 +30,23 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &TunnelResource{}
 var _ resource.ResourceWithImportState = &TunnelResource{}
+var _ resource.ResourceWithModifyPlan = &TunnelResource{}
+var _ resource.ResourceWithValidateConfig = &TunnelResource{}
+
+var (
+	tunnelDeviceTypes  = []string{"ASA", "FTD", "ISR", "Meraki MX", "Viptela", "other"}
+	tunnelServiceTypes = []string{tunnelServiceSIG, tunnelServicePrivateAccess}
+	tunnelAuthTypes    = []string{"PSK"}
+	tunnelProtocols    = []string{"IPSec"}
+)
+
+const (
+	tunnelServiceSIG           = "SIG"
+	tunnelServicePrivateAccess = "Private Access"
+)
+
+// tunnelSecretPath is the path of the pre-shared key in the tunnel schema.
+var tunnelSecretPath = path.Root("client").AtName("authentication").AtName("parameters").AtName("secret")
 
 func NewTunnelResource() resource.Resource {
 	return &TunnelResource{}
@@ -30,23 +54,26 @@ func NewTunnelResource() resource.Resource {
 
 // ExampleResource defines the resource implementation.
 type TunnelResource struct {
-	client *umbrella.Client
+	client *apiClient
 }
 
 // ExampleResourceModel describes the resource data model.
 type TunnelResourceModel struct {
-	Id           types.Int64  `tfsdk:"id"`
-	Uri          types.String `tfsdk:"uri"`
-	Name         types.String `tfsdk:"name"`
-	SiteOriginId types.Int64  `tfsdk:"site_origin_id"`
-	Client       types.Object `tfsdk:"client"`
-	Transport    types.Object `tfsdk:"transport"`
-	ServiceType  types.String `tfsdk:"service_type"`
-	NetworkCidrs types.List   `tfsdk:"network_cidrs"`
+	Id              types.Int64  `tfsdk:"id"`
+	Uri             types.String `tfsdk:"uri"`
+	Name            types.String `tfsdk:"name"`
+	SiteOriginId    types.Int64  `tfsdk:"site_origin_id"`
+	Client          types.Object `tfsdk:"client"`
+	Transport       types.Object `tfsdk:"transport"`
+	ServiceType     types.String `tfsdk:"service_type"`
+	NetworkCidrs    types.List   `tfsdk:"network_cidrs"`
+	RotationTrigger types.String `tfsdk:"rotation_trigger"`
 	//Meta         *TunnelMetaResourceModel   `tfsdk:"meta"`
-	ModifiedAt  types.String `tfsdk:"modified_at"`
-	CreatedAt   types.String `tfsdk:"created_at"`
-	LastUpdated types.String `tfsdk:"last_updated"`
+	ModifiedAt  types.String   `tfsdk:"modified_at"`
+	CreatedAt   types.String   `tfsdk:"created_at"`
+	LastUpdated types.String   `tfsdk:"last_updated"`
+	OrgId       types.Int64    `tfsdk:"org_id"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 type TunnelClientResourceModel struct {
@@ -144,8 +171,12 @@ func (r *TunnelResource) Schema(ctx context.Context, req resource.SchemaRequest,
 
 				Attributes: map[string]schema.Attribute{
 					"device_type": schema.StringAttribute{
-						Computed: true,
-						Optional: true,
+						MarkdownDescription: "The type of the device at the client end of the Tunnel, one of " + quotedList(tunnelDeviceTypes),
+						Computed:            true,
+						Optional:            true,
+						Validators: []validator.String{
+							stringOneOf(tunnelDeviceTypes...),
+						},
 						PlanModifiers: []planmodifier.String{
 							stringplanmodifier.UseStateForUnknown(),
 						},
@@ -156,8 +187,12 @@ func (r *TunnelResource) Schema(ctx context.Context, req resource.SchemaRequest,
 
 						Attributes: map[string]schema.Attribute{
 							"type": schema.StringAttribute{
-								Computed: true,
-								Optional: true,
+								MarkdownDescription: "The authentication type, one of " + quotedList(tunnelAuthTypes),
+								Computed:            true,
+								Optional:            true,
+								Validators: []validator.String{
+									stringOneOf(tunnelAuthTypes...),
+								},
 							},
 							"parameters": schema.SingleNestedAttribute{
 								Computed: true,
@@ -178,15 +213,24 @@ func (r *TunnelResource) Schema(ctx context.Context, req resource.SchemaRequest,
 										Computed: true,
 									},
 									"secret": schema.StringAttribute{
-										Computed: true,
-										Optional: true,
+										MarkdownDescription: "The IKE pre-shared key. Changing it rotates the tunnel credentials",
+										Computed:            true,
+										Optional:            true,
+										Sensitive:           true,
+										Validators: []validator.String{
+											stringCheck("value must be a valid Umbrella pre-shared key", validateTunnelSecret),
+										},
 										PlanModifiers: []planmodifier.String{
 											stringplanmodifier.UseStateForUnknown(),
 										},
 									},
 									"id_prefix": schema.StringAttribute{
-										Computed: true,
-										Optional: true,
+										MarkdownDescription: "The prefix of the tunnel ID. Umbrella appends the organization domain to it",
+										Computed:            true,
+										Optional:            true,
+										Validators: []validator.String{
+											stringCheck("value must be a valid tunnel ID prefix", validateTunnelIDPrefix),
+										},
 										PlanModifiers: []planmodifier.String{
 											stringplanmodifier.UseStateForUnknown(),
 										},
@@ -199,13 +243,19 @@ func (r *TunnelResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 			"transport": schema.SingleNestedAttribute{
 				Computed: true,
+				Optional: true,
 				PlanModifiers: []planmodifier.Object{
 					objectplanmodifier.UseStateForUnknown(),
 				},
 
 				Attributes: map[string]schema.Attribute{
 					"protocol": schema.StringAttribute{
-						Computed: true,
+						MarkdownDescription: "The tunnel protocol, one of " + quotedList(tunnelProtocols),
+						Computed:            true,
+						Optional:            true,
+						Validators: []validator.String{
+							stringOneOf(tunnelProtocols...),
+						},
 						PlanModifiers: []planmodifier.String{
 							stringplanmodifier.UseStateForUnknown(),
 						},
@@ -217,9 +267,12 @@ func (r *TunnelResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 
 			"service_type": schema.StringAttribute{
-				MarkdownDescription: "Specifies whether the Site is default or not",
+				MarkdownDescription: "The service the Tunnel connects to, one of " + quotedList(tunnelServiceTypes) + ". `" + tunnelServicePrivateAccess + "` requires `network_cidrs`",
 				Computed:            true,
 				Optional:            true,
+				Validators: []validator.String{
+					stringOneOf(tunnelServiceTypes...),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -237,8 +290,25 @@ func (r *TunnelResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"network_cidrs": schema.ListAttribute{
-				ElementType: types.StringType,
-				Optional:    true,
+				MarkdownDescription: "The IPv4 networks, in CIDR notation, that are reachable through the Tunnel",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					stringListCheck("values must be IPv4 networks in CIDR notation", validateTunnelCIDR),
+				},
+			},
+			"rotation_trigger": schema.StringAttribute{
+				MarkdownDescription: "Any value. Changing it rotates the pre-shared key of the Tunnel, to `secret` when that changes too, or to a generated secret when `secret` is not configured",
+				Optional:            true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the Tunnel. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
 			},
 			//"meta": schema.SingleNestedAttribute{
 			//	Computed:   true,
@@ -246,8 +316,67 @@ func (r *TunnelResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			//	Attributes: map[string]schema.Attribute{},
 			//},
 		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
+
+func (r *TunnelResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var serviceType types.String
+	var networkcidrs types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("service_type"), &serviceType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("network_cidrs"), &networkcidrs)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if serviceType.ValueString() == tunnelServicePrivateAccess && !networkcidrs.IsUnknown() && len(networkcidrs.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("network_cidrs"),
+			"Missing Tunnel Network CIDRs",
+			"network_cidrs must list the networks behind the tunnel when service_type is \""+tunnelServicePrivateAccess+"\"",
+		)
+	}
+}
+
+// validateTunnelCIDR checks that cidr is an IPv4 network in CIDR notation,
+// with the address being the network address.
+func validateTunnelCIDR(cidr string) error {
+	ip, network, err := net.ParseCIDR(cidr)
+	if err != nil || ip.To4() == nil {
+		return fmt.Errorf("must be an IPv4 network in CIDR notation, got %q", cidr)
+	}
+
+	if !network.IP.Equal(ip) {
+		return fmt.Errorf("%s has host bits set, did you mean %s?", cidr, network)
+	}
+	return nil
+}
+
+// validateTunnelIDPrefix checks the local part of a tunnel ID: 3 to 64
+// letters, digits, dots, hyphens and underscores, starting with a letter or
+// digit. Umbrella adds the @ and the organization domain itself.
+func validateTunnelIDPrefix(prefix string) error {
+	if strings.Contains(prefix, "@") {
+		return fmt.Errorf("must not contain @, Umbrella appends the organization domain itself, got %q", prefix)
+	}
+	if !tunnelIDPrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("must be 3 to 64 letters, digits, dots, hyphens or underscores, starting with a letter or digit, got %q", prefix)
+	}
+	return nil
+}
+
+var tunnelIDPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{2,63}$`)
+
 func buildTunnelItem(data TunnelResourceModel, client TunnelClientResourceModel, auth TunnelAuthResourceModel, parameters TunnelAuthParamsResourceModel, transport TunnelTransResourceModel, networkcidrs []string) umbrella.NetworkTunnel {
 
 	tunnelItem := umbrella.NetworkTunnel{Name: data.Name.ValueString(),
@@ -267,10 +396,8 @@ func buildTunnelItem(data TunnelResourceModel, client TunnelClientResourceModel,
 		tunnelItem.Client.DeviceType = client.DeviceType.ValueString()
 
 	}
-	if !parameters.Secret.IsNull() && !parameters.Secret.IsUnknown() && !parameters.Secret.Equal(types.StringValue("")) {
-		//tunnelItem.Client.Authentication.Parameters.Secret = parameters.Secret.ValueString()
-
-	}
+	// The secret is not part of the tunnel update, Update rotates it through
+	// the tunnel credentials instead.
 	if !data.SiteOriginId.IsNull() && !data.SiteOriginId.IsUnknown() && !data.SiteOriginId.Equal(types.Int64Value(0)) {
 		tunnelItem.SiteOriginId = data.SiteOriginId.ValueInt64()
 
@@ -289,12 +416,12 @@ func (r *TunnelResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 
-	client, ok := req.ProviderData.(*umbrella.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -303,6 +430,44 @@ func (r *TunnelResource) Configure(ctx context.Context, req resource.ConfigureRe
 	r.client = client
 }
 
+func (r *TunnelResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to rotate on create or destroy.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planTrigger, stateTrigger, configSecret, planSecret, stateSecret types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("rotation_trigger"), &planTrigger)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("rotation_trigger"), &stateTrigger)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, tunnelSecretPath, &configSecret)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, tunnelSecretPath, &planSecret)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, tunnelSecretPath, &stateSecret)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if planTrigger.Equal(stateTrigger) {
+		return
+	}
+
+	if configSecret.IsNull() {
+		// The provider generates the new secret during apply.
+		if !planSecret.IsUnknown() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tunnelSecretPath, types.StringUnknown())...)
+		}
+		return
+	}
+
+	if configSecret.Equal(stateSecret) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("rotation_trigger"),
+			"Tunnel Secret Not Changed",
+			"rotation_trigger changed, but client.authentication.parameters.secret is still set to the current secret. "+
+				"Set a new secret, or remove it from the configuration to have the provider generate one.",
+		)
+	}
+}
+
 func (r *TunnelResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data *TunnelResourceModel
 
@@ -369,13 +534,25 @@ func (r *TunnelResource) Create(ctx context.Context, req resource.CreateRequest,
 		tunnelItem.NetworkCIDRs = networkcidrs
 	}
 
-	tunnel, err := r.client.CreateTunnel(tunnelItem, &r.client.Token)
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	tunnel, err := api.CreateTunnel(tunnelItem, nil)
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating Tunnel",
-			"Could not create Tunnel, unexpected error: "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "create", "Umbrella Tunnel", "", createTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Creating Umbrella Tunnel", "Could not create Umbrella Tunnel", err, apiErrorPaths{http.StatusConflict: path.Root("name")})
 		return
 	}
 
@@ -427,6 +604,7 @@ func (r *TunnelResource) Create(ctx context.Context, req resource.CreateRequest,
 	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	data.Id = types.Int64Value(int64(tunnel.Id))
+	data.OrgId = api.orgIDValue()
 
 	tflog.Trace(ctx, "created a resource")
 
@@ -456,12 +634,31 @@ func (r *TunnelResource) Read(ctx context.Context, req resource.ReadRequest, res
 	var stateparameters TunnelAuthParamsResourceModel
 	resp.Diagnostics.Append(stateauth.Parameters.As(ctx, &stateparameters, basetypes.ObjectAsOptions{UnhandledUnknownAsEmpty: true, UnhandledNullAsEmpty: true})...)
 
-	tunnel, err := r.client.GetTunnel(data.Id.ValueInt64(), &r.client.Token)
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	tunnel, err := api.GetTunnel(data.Id.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Tunnel no longer exists, removing it from state", map[string]interface{}{
+			"id": data.Id.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Reading Umbrella Tunnel",
-			"Could not read Umbrella Tunnel ID "+strconv.FormatInt(data.Id.ValueInt64(), 10)+": "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "read", "Umbrella Tunnel", strconv.FormatInt(data.Id.ValueInt64(), 10), readTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Tunnel", "Could not read Umbrella Tunnel ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, nil)
 		return
 	}
 
@@ -503,6 +700,7 @@ func (r *TunnelResource) Read(ctx context.Context, req resource.ReadRequest, res
 	data.CreatedAt = types.StringValue(tunnel.CreatedAt)
 
 	data.Id = types.Int64Value(int64(tunnel.Id))
+	data.OrgId = api.orgIDValue()
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -560,24 +758,99 @@ func (r *TunnelResource) Update(ctx context.Context, req resource.UpdateRequest,
 	var stateparameters TunnelAuthParamsResourceModel
 	resp.Diagnostics.Append(stateauth.Parameters.As(ctx, &stateparameters, basetypes.ObjectAsOptions{UnhandledUnknownAsEmpty: true, UnhandledNullAsEmpty: true})...)
 
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	api := r.client.forOrg(statedata.OrgId).withContext(ctx)
+
 	//siteid, _ := strconv.Atoi(data.SiteId.ValueString())
 	tunnelItem := buildTunnelItem(*data, client, auth, parameters, transport, networkcidrs)
 
-	_, err := r.client.UpdateTunnel(statedata.Id.ValueInt64(), tunnelItem, &r.client.Token)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating Umbrella Tunnel"+strconv.FormatInt(statedata.Id.ValueInt64(), 10),
-			"Could not update tunnel, unexpected error: "+err.Error(),
-		)
+	// The tunnel is only sent when one of its fields changes, not when the
+	// change is limited to the secret, which is rotated below.
+	var statecidrs []string
+	resp.Diagnostics.Append(statedata.NetworkCidrs.ElementsAs(ctx, &statecidrs, false)...)
+
+	var statetransport TunnelTransResourceModel
+	resp.Diagnostics.Append(statedata.Transport.As(ctx, &statetransport, basetypes.ObjectAsOptions{UnhandledUnknownAsEmpty: true, UnhandledNullAsEmpty: true})...)
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tunnel, err := r.client.GetTunnel(statedata.Id.ValueInt64(), &r.client.Token)
+	var err error
+	if !reflect.DeepEqual(tunnelItem, buildTunnelItem(*statedata, stateclient, stateauth, stateparameters, statetransport, statecidrs)) {
+		_, err = api.UpdateTunnel(statedata.Id.ValueInt64(), tunnelItem, nil)
+		if err != nil {
+			if deadlineExceeded(ctx) {
+				addTimeoutError(&resp.Diagnostics, "update", "Umbrella Tunnel", strconv.FormatInt(statedata.Id.ValueInt64(), 10), updateTimeout)
+				return
+			}
+			addAPIError(&resp.Diagnostics, "Error Updating Umbrella Tunnel", "Could not update Umbrella Tunnel ID "+strconv.FormatInt(statedata.Id.ValueInt64(), 10), err, apiErrorPaths{http.StatusConflict: path.Root("name")})
+			return
+		}
+	}
+
+	// A configured secret that differs from the state is rotated to as is.
+	// Changing only rotation_trigger rotates to a generated secret.
+	secret := stateparameters.Secret
+	secretChanged := !parameters.Secret.IsUnknown() && !parameters.Secret.IsNull() && !parameters.Secret.Equal(stateparameters.Secret)
+	if secretChanged || !data.RotationTrigger.Equal(statedata.RotationTrigger) {
+		newSecret := parameters.Secret.ValueString()
+		if !secretChanged {
+			newSecret, err = generateTunnelSecret(generatedTunnelSecretLength)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error Generating Umbrella Tunnel Secret",
+					"Could not generate a new secret for Tunnel ID "+strconv.FormatInt(statedata.Id.ValueInt64(), 10)+": "+err.Error(),
+				)
+				return
+			}
+		} else if err := validateTunnelSecret(newSecret); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				tunnelSecretPath,
+				"Invalid Umbrella Tunnel Secret",
+				"The secret "+err.Error(),
+			)
+			return
+		}
+
+		stale, err := rotateTunnelSecret(api, statedata.Id.ValueInt64(), newSecret)
+		if err != nil {
+			if deadlineExceeded(ctx) {
+				addTimeoutError(&resp.Diagnostics, "update", "Umbrella Tunnel", strconv.FormatInt(statedata.Id.ValueInt64(), 10), updateTimeout)
+				return
+			}
+			addAPIError(&resp.Diagnostics, "Error Rotating Umbrella Tunnel Secret", "Could not rotate the secret of Umbrella Tunnel ID "+strconv.FormatInt(statedata.Id.ValueInt64(), 10), err, apiErrorPaths{http.StatusBadRequest: tunnelSecretPath})
+			return
+		}
+		if len(stale) > 0 {
+			resp.Diagnostics.AddWarning(
+				"Old Umbrella Tunnel Credentials Not Deleted",
+				fmt.Sprintf("The secret of Tunnel ID %d was rotated, but old credentials %s could not be deleted and still accept the previous secret. Delete them in the Umbrella dashboard.",
+					statedata.Id.ValueInt64(), strings.Join(stale, ", ")),
+			)
+		}
+
+		tflog.Info(ctx, "Rotated Umbrella Tunnel secret", map[string]interface{}{
+			"id": statedata.Id.ValueInt64(),
+		})
+		secret = types.StringValue(newSecret)
+	}
+
+	tunnel, err := api.GetTunnel(statedata.Id.ValueInt64(), nil)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Reading Umbrella Tunnel",
-			"Could not read Umbrella Tunnel ID "+strconv.FormatInt(statedata.Id.ValueInt64(), 10)+": "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "update", "Umbrella Tunnel", strconv.FormatInt(statedata.Id.ValueInt64(), 10), updateTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella Tunnel", "Could not read Umbrella Tunnel ID "+strconv.FormatInt(statedata.Id.ValueInt64(), 10), err, nil)
 		return
 	}
 
@@ -588,7 +861,7 @@ func (r *TunnelResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	parameters.Id = types.StringValue(tunnel.Client.Authentication.Parameters.Id)
 	parameters.ModifiedAt = types.StringValue(tunnel.Client.Authentication.Parameters.ModifiedAt)
-	parameters.Secret = stateparameters.Secret
+	parameters.Secret = secret
 
 	params, _ := types.ObjectValueFrom(ctx, ParamsAttrTypes(), parameters)
 
@@ -615,6 +888,7 @@ func (r *TunnelResource) Update(ctx context.Context, req resource.UpdateRequest,
 	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	data.Id = types.Int64Value(int64(tunnel.Id))
+	data.OrgId = api.orgIDValue()
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -632,20 +906,53 @@ func (r *TunnelResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	err := r.client.DeleteTunnel(data.Id.ValueInt64(), &r.client.Token)
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.forOrg(data.OrgId).withContext(ctx).DeleteTunnel(data.Id.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella Tunnel is already gone", map[string]interface{}{
+			"id": data.Id.ValueInt64(),
+		})
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Deleting Umbrella Tunnel",
-			"Could not delete Tunnel, unexpected error: "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "delete", "Umbrella Tunnel", strconv.FormatInt(data.Id.ValueInt64(), 10), deleteTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella Tunnel", "Could not delete Umbrella Tunnel ID "+strconv.FormatInt(data.Id.ValueInt64(), 10), err, nil)
 		return
 	}
 }
 
 func (r *TunnelResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 
-	id, _ := strconv.ParseInt(req.ID, 10, 64)
+	orgid, id, diags := resolveImportID(r.client, req.ID, "Tunnel", func(api *apiClient, name string) ([]int64, error) {
+		tunnels, err := api.GetTunnels(nil)
+		if err != nil {
+			return nil, err
+		}
+		var ids []int64
+		for _, tunnel := range tunnels {
+			if tunnel.Name == name {
+				ids = append(ids, tunnel.Id)
+			}
+		}
+		return ids, nil
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
 	//resource.ImportStatePassthroughID(ctx, path.Root("site_id"), req, resp)
 }
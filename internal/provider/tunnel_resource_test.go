@@ -0,0 +1,408 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTunnelResource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var tunnelID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccTunnelResourceConfig(fake, "branch-1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_tunnel.test", "name", "branch-1"),
+					resource.TestCheckResourceAttr("umbrella_tunnel.test", "client.device_type", "ASA"),
+					resource.TestCheckResourceAttr("umbrella_tunnel.test", "service_type", "SIG"),
+					resource.TestCheckResourceAttr("umbrella_tunnel.test", "transport.protocol", "IPSec"),
+					resource.TestCheckResourceAttr("umbrella_tunnel.test", "network_cidrs.#", "1"),
+					resource.TestCheckResourceAttrSet("umbrella_tunnel.test", "id"),
+					resource.TestCheckResourceAttrSet("umbrella_tunnel.test", "client.authentication.parameters.id"),
+					resource.TestCheckResourceAttrPair("umbrella_tunnel.test", "site_origin_id", "umbrella_site.test", "origin_id"),
+					testAccCaptureAttr("umbrella_tunnel.test", "id", &tunnelID),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "umbrella_tunnel.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateID("umbrella_tunnel.test", "id"),
+				ImportStateVerify: true,
+				// The API never returns the secret, and the ID prefix only exists in configuration.
+				ImportStateVerifyIgnore: []string{
+					"last_updated",
+					"client.authentication.parameters.secret",
+					"client.authentication.parameters.id_prefix",
+				},
+			},
+			{
+				ResourceName:      "umbrella_tunnel.test",
+				ImportState:       true,
+				ImportStateId:     "name:branch-1",
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"last_updated",
+					"client.authentication.parameters.secret",
+					"client.authentication.parameters.id_prefix",
+				},
+			},
+			// Update and Read testing
+			{
+				Config: testAccTunnelResourceConfig(fake, "branch-2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_tunnel.test", "name", "branch-2"),
+					testAccCheckAttrUnchanged("umbrella_tunnel.test", "id", &tunnelID),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccTunnelResource_rotateSecret(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var tunnelID, generated string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTunnelResourceConfigSecret(fake, "Sup3rSecretPassw0rd", "1"),
+				Check:  testAccCaptureAttr("umbrella_tunnel.test", "id", &tunnelID),
+			},
+			// A new configured secret replaces the old one, without updating
+			// the tunnel itself.
+			{
+				PreConfig: fake.clearRequests,
+				Config:    testAccTunnelResourceConfigSecret(fake, "N3wSecretPassw0rd", "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_tunnel.test", "client.authentication.parameters.secret", "N3wSecretPassw0rd"),
+					testAccCheckTunnelSecrets(fake, &tunnelID, "N3wSecretPassw0rd"),
+					testAccCheckNoRequests(fake, "PUT", "/deployments/v2/tunnels"),
+				),
+			},
+			// Changing only the trigger rotates to a generated secret.
+			{
+				Config: testAccTunnelResourceConfigSecret(fake, "", "2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckNoRequests(fake, "PUT", "/deployments/v2/tunnels"),
+					testAccCaptureAttr("umbrella_tunnel.test", "client.authentication.parameters.secret", &generated),
+					func(*terraform.State) error {
+						if err := validateTunnelSecret(generated); err != nil {
+							return fmt.Errorf("generated secret %s", err)
+						}
+						return testAccCheckTunnelSecrets(fake, &tunnelID, generated)(nil)
+					},
+				),
+			},
+			// Without a trigger change the generated secret stays.
+			{
+				Config: testAccTunnelResourceConfigSecret(fake, "", "2"),
+				Check:  testAccCheckAttrUnchanged("umbrella_tunnel.test", "client.authentication.parameters.secret", &generated),
+			},
+		},
+	})
+}
+
+func TestAccTunnelResource_rotateSecretFailureKeepsOldCredential(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var tunnelID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTunnelResourceConfigSecret(fake, "Sup3rSecretPassw0rd", "1"),
+				Check:  testAccCaptureAttr("umbrella_tunnel.test", "id", &tunnelID),
+			},
+			{
+				PreConfig:   func() { fake.fail("POST", "/deployments/v2/tunnels/"+tunnelID+"/keys", 500, 1) },
+				Config:      testAccTunnelResourceConfigSecret(fake, "N3wSecretPassw0rd", "1"),
+				ExpectError: regexp.MustCompile(`Error Rotating Umbrella Tunnel Secret`),
+			},
+			{
+				Config: testAccTunnelResourceConfigSecret(fake, "Sup3rSecretPassw0rd", "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_tunnel.test", "client.authentication.parameters.secret", "Sup3rSecretPassw0rd"),
+					testAccCheckTunnelSecrets(fake, &tunnelID, "Sup3rSecretPassw0rd"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTunnelResource_rotateSecretUnchanged(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTunnelResourceConfigSecret(fake, "Sup3rSecretPassw0rd", "1"),
+			},
+			{
+				Config:      testAccTunnelResourceConfigSecret(fake, "Sup3rSecretPassw0rd", "2"),
+				ExpectError: regexp.MustCompile(`Tunnel Secret Not Changed`),
+			},
+		},
+	})
+}
+
+func testAccCheckTunnelSecrets(fake *fakeUmbrella, tunnelID *string, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		got := fake.tunnelSecrets(*tunnelID)
+		if !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("tunnel %s accepts secrets %q, want %q", *tunnelID, got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccTunnelResource_deletedOutsideTerraform(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var tunnelID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTunnelResourceConfig(fake, "branch-1"),
+				Check:  testAccCaptureAttr("umbrella_tunnel.test", "id", &tunnelID),
+			},
+			{
+				PreConfig:          func() { fake.remove(tunnelID) },
+				Config:             testAccTunnelResourceConfig(fake, "branch-1"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccTunnelResource_readTimeout(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	config := testAccTunnelResourceConfigExtra(fake, "branch-1", `
+  timeouts {
+    read = "1s"
+  }
+`)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig:   func() { fake.stall("GET", "/deployments/v2/tunnels/", 5*time.Second, 1) },
+				Config:      config,
+				ExpectError: regexp.MustCompile(`Reading Umbrella Tunnel ID \d+ did not finish within the read timeout`),
+			},
+		},
+	})
+}
+
+func TestAccTunnelResource_invalidConfig(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	config := func(deviceType, serviceType, idPrefix, cidrs, protocol string) string {
+		return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_tunnel" "test" {
+  name          = "branch-1"
+  service_type  = %[2]q
+  network_cidrs = %[4]s
+
+  client = {
+    device_type = %[1]q
+    authentication = {
+      type = "PSK"
+      parameters = {
+        id_prefix = %[3]q
+        secret    = "Sup3rSecretPassw0rd"
+      }
+    }
+  }
+
+  transport = {
+    protocol = %[5]q
+  }
+}
+`, deviceType, serviceType, idPrefix, cidrs, protocol)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("asa", "SIG", "branch", `["10.10.0.0/24"]`, "IPSec"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Did you mean "ASA"\?`),
+			},
+			{
+				Config:      config("ASA", "Secure Internet", "branch", `["10.10.0.0/24"]`, "IPSec"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`service_type must be one of "SIG", "Private Access"`),
+			},
+			{
+				Config:      config("ASA", "SIG", "branch@example.com", `["10.10.0.0/24"]`, "IPSec"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must not contain @`),
+			},
+			{
+				Config:      config("ASA", "SIG", "branch", `["10.10.0.1/24"]`, "IPSec"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`did you mean 10\.10\.0\.0/24`),
+			},
+			{
+				Config:      config("ASA", "SIG", "branch", `["10.10.0.0/24"]`, "GRE"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`transport.protocol must be one of "IPSec"`),
+			},
+			{
+				Config:      config("ASA", "Private Access", "branch", `[]`, "IPSec"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Missing Tunnel Network CIDRs`),
+			},
+		},
+	})
+}
+
+func TestValidateTunnelCIDR(t *testing.T) {
+	cases := []struct {
+		cidr    string
+		wantErr bool
+	}{
+		{cidr: "10.10.0.0/24"},
+		{cidr: "0.0.0.0/0"},
+		{cidr: "192.168.1.1/32"},
+		{cidr: "10.10.0.1/24", wantErr: true},
+		{cidr: "10.10.0.0", wantErr: true},
+		{cidr: "2001:db8::/32", wantErr: true},
+		{cidr: "10.10.0.0/33", wantErr: true},
+	}
+
+	for _, c := range cases {
+		err := validateTunnelCIDR(c.cidr)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: got error %v, want error %t", c.cidr, err, c.wantErr)
+		}
+	}
+}
+
+func TestValidateTunnelIDPrefix(t *testing.T) {
+	cases := []struct {
+		prefix  string
+		wantErr bool
+	}{
+		{prefix: "branch"},
+		{prefix: "branch-1.dc_a"},
+		{prefix: "ab", wantErr: true},
+		{prefix: "-branch", wantErr: true},
+		{prefix: "branch@example.com", wantErr: true},
+		{prefix: "branch 1", wantErr: true},
+	}
+
+	for _, c := range cases {
+		err := validateTunnelIDPrefix(c.prefix)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%q: got error %v, want error %t", c.prefix, err, c.wantErr)
+		}
+	}
+}
+
+func TestAccTunnelResource_notFoundOnImport(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccTunnelResourceConfig(fake, "branch-1"),
+				ResourceName:  "umbrella_tunnel.test",
+				ImportState:   true,
+				ImportStateId: "999999",
+				ExpectError:   regexp.MustCompile(`Cannot import non-existent remote object`),
+			},
+		},
+	})
+}
+
+// testAccTunnelResourceConfigSecret configures the tunnel with secret, or
+// without one when secret is empty, and with rotation_trigger set to trigger.
+func testAccTunnelResourceConfigSecret(fake *fakeUmbrella, secret, trigger string) string {
+	secretLine := ""
+	if secret != "" {
+		secretLine = fmt.Sprintf("secret    = %q", secret)
+	}
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_site" "test" {
+  name = "tunnel-site"
+}
+
+resource "umbrella_tunnel" "test" {
+  name             = "branch-1"
+  site_origin_id   = umbrella_site.test.origin_id
+  network_cidrs    = ["10.10.0.0/24"]
+  rotation_trigger = %[2]q
+
+  client = {
+    device_type = "ASA"
+    authentication = {
+      type = "PSK"
+      parameters = {
+        id_prefix = "branch"
+        %[1]s
+      }
+    }
+  }
+}
+`, secretLine, trigger)
+}
+
+func testAccTunnelResourceConfig(fake *fakeUmbrella, name string) string {
+	return testAccTunnelResourceConfigExtra(fake, name, "")
+}
+
+// testAccTunnelResourceConfigExtra appends extra to the body of the tunnel.
+func testAccTunnelResourceConfigExtra(fake *fakeUmbrella, name, extra string) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_site" "test" {
+  name = "tunnel-site"
+}
+
+resource "umbrella_tunnel" "test" {
+  name           = %[1]q
+  site_origin_id = umbrella_site.test.origin_id
+  network_cidrs  = ["10.10.0.0/24"]
+
+  client = {
+    device_type = "ASA"
+    authentication = {
+      type = "PSK"
+      parameters = {
+        id_prefix = "branch"
+        secret    = "Sup3rSecretPassw0rd"
+      }
+    }
+  }
+%[2]s}
+`, name, extra)
+}
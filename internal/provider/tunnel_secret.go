@@ -0,0 +1,117 @@
+package umbrellaprovider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+const (
+	tunnelSecretMinLength = 16
+	tunnelSecretMaxLength = 64
+
+	// generatedTunnelSecretLength is the length of the secrets the provider
+	// generates.
+	generatedTunnelSecretLength = 32
+
+	tunnelSecretLower   = "abcdefghijklmnopqrstuvwxyz"
+	tunnelSecretUpper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	tunnelSecretDigits  = "0123456789"
+	tunnelSecretCharset = tunnelSecretLower + tunnelSecretUpper + tunnelSecretDigits
+)
+
+// validateTunnelSecret checks secret against Umbrella's pre-shared key rules:
+// 16 to 64 letters and digits, with at least one upper case letter, one lower
+// case letter and one digit.
+func validateTunnelSecret(secret string) error {
+	if len(secret) < tunnelSecretMinLength || len(secret) > tunnelSecretMaxLength {
+		return fmt.Errorf("must be between %d and %d characters long, got %d", tunnelSecretMinLength, tunnelSecretMaxLength, len(secret))
+	}
+
+	var lower, upper, digit bool
+	for _, c := range secret {
+		switch {
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= '0' && c <= '9':
+			digit = true
+		default:
+			return fmt.Errorf("must only contain letters and digits, got %q", c)
+		}
+	}
+
+	if !lower || !upper || !digit {
+		return fmt.Errorf("must contain at least one upper case letter, one lower case letter and one digit")
+	}
+	return nil
+}
+
+// generateTunnelSecret returns a random secret of length characters that
+// satisfies validateTunnelSecret.
+func generateTunnelSecret(length int) (string, error) {
+	if length < tunnelSecretMinLength || length > tunnelSecretMaxLength {
+		return "", fmt.Errorf("secret length must be between %d and %d, got %d", tunnelSecretMinLength, tunnelSecretMaxLength, length)
+	}
+
+	for {
+		secret := make([]byte, length)
+		for i := range secret {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(tunnelSecretCharset))))
+			if err != nil {
+				return "", err
+			}
+			secret[i] = tunnelSecretCharset[n.Int64()]
+		}
+
+		// Rejecting the rare secret that misses a character class keeps
+		// every character uniformly distributed.
+		if validateTunnelSecret(string(secret)) == nil {
+			return string(secret), nil
+		}
+	}
+}
+
+// rotateTunnelSecret replaces the credentials of a tunnel with one holding
+// secret. The old credentials are only deleted once the API lists the new
+// one, so that a failed rotation never leaves the tunnel without a key. It
+// returns the IDs of old credentials that could not be deleted.
+func rotateTunnelSecret(api *apiClient, tunnelID int64, secret string) ([]string, error) {
+	old, err := api.GetTunnelCredentials(tunnelID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not list current credentials: %w", err)
+	}
+
+	created, err := api.CreateTunnelCredential(tunnelID, secret, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not add new credential: %w", err)
+	}
+
+	current, err := api.GetTunnelCredentials(tunnelID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not confirm new credential %s, the old credentials were kept: %w", created.Id, err)
+	}
+	confirmed := false
+	for _, credential := range current {
+		if credential.Id == created.Id {
+			confirmed = true
+			break
+		}
+	}
+	if !confirmed {
+		return nil, fmt.Errorf("new credential %s is not listed for the tunnel, the old credentials were kept", created.Id)
+	}
+
+	var stale []string
+	for _, credential := range old {
+		if credential.Id == created.Id {
+			continue
+		}
+		err := api.DeleteTunnelCredential(tunnelID, credential.Id, nil)
+		if err != nil && !isNotFound(err) {
+			stale = append(stale, credential.Id)
+		}
+	}
+	return stale, nil
+}
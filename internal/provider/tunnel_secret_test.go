@@ -0,0 +1,49 @@
+package umbrellaprovider
+
+import "testing"
+
+func TestValidateTunnelSecret(t *testing.T) {
+	tests := map[string]bool{
+		"Sup3rSecretPassw0rd":            true,
+		"Sh0rtSecret":                    false,
+		"nouppercaseordigits1234":        false,
+		"NOLOWERCASE12345678":            false,
+		"NoDigitsInThisSecret":           false,
+		"Has-Special-Chars-123":          false,
+		"Aa1" + string(make([]byte, 62)): false,
+	}
+
+	for secret, valid := range tests {
+		err := validateTunnelSecret(secret)
+		if valid && err != nil {
+			t.Errorf("validateTunnelSecret(%q) = %v, want nil", secret, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("validateTunnelSecret(%q) = nil, want an error", secret)
+		}
+	}
+}
+
+func TestGenerateTunnelSecret(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		secret, err := generateTunnelSecret(generatedTunnelSecretLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(secret) != generatedTunnelSecretLength {
+			t.Fatalf("generated secret has length %d, want %d", len(secret), generatedTunnelSecretLength)
+		}
+		if err := validateTunnelSecret(secret); err != nil {
+			t.Fatalf("generated secret %q is invalid: %v", secret, err)
+		}
+		if seen[secret] {
+			t.Fatalf("generated secret %q twice", secret)
+		}
+		seen[secret] = true
+	}
+
+	if _, err := generateTunnelSecret(8); err == nil {
+		t.Error("generateTunnelSecret(8) succeeded, want an error")
+	}
+}
@@ -0,0 +1,193 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/olegunza/umbrella-api-go/umbrella"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TunnelStateDataSource{}
+var _ datasource.DataSourceWithConfigure = &TunnelStateDataSource{}
+
+func NewTunnelStateDataSource() datasource.DataSource {
+	return &TunnelStateDataSource{}
+}
+
+type TunnelStateDataSource struct {
+	client *apiClient
+}
+
+// TunnelStateDataSourceModel describes the data source data model.
+type TunnelStateDataSourceModel struct {
+	Id          types.Int64                  `tfsdk:"id"`
+	TunnelId    types.Int64                  `tfsdk:"tunnel_id"`
+	Status      types.String                 `tfsdk:"status"`
+	ModifiedAt  types.String                 `tfsdk:"modified_at"`
+	DataCenters []TunnelDataCenterStateModel `tfsdk:"data_centers"`
+}
+
+type TunnelDataCenterStateModel struct {
+	Dc                types.String `tfsdk:"dc"`
+	City              types.String `tfsdk:"city"`
+	Fqdn              types.String `tfsdk:"fqdn"`
+	PeerIp            types.String `tfsdk:"peer_ip"`
+	IkeStatus         types.String `tfsdk:"ike_status"`
+	IpsecStatus       types.String `tfsdk:"ipsec_status"`
+	LastEstablishedAt types.String `tfsdk:"last_established_at"`
+}
+
+func (d *TunnelStateDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tunnel_state"
+}
+
+func (d *TunnelStateDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Tunnel state data source. Returns the IKE and IPsec status of a tunnel in every data center it peers with",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the Tunnel",
+				Computed:            true,
+			},
+			"tunnel_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the Tunnel to read the state of",
+				Required:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The overall status of the Tunnel",
+				Computed:            true,
+			},
+			"modified_at": schema.StringAttribute{
+				MarkdownDescription: "The date and time (ISO8601 timestamp) when the state was last updated",
+				Computed:            true,
+			},
+			"data_centers": schema.ListNestedAttribute{
+				MarkdownDescription: "The state of the Tunnel in each data center",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"dc": schema.StringAttribute{
+							MarkdownDescription: "The name of the data center, as in `umbrella_dclist`",
+							Computed:            true,
+						},
+						"city": schema.StringAttribute{
+							MarkdownDescription: "The city of the data center. Null if `umbrella_dclist` does not list the data center",
+							Computed:            true,
+						},
+						"fqdn": schema.StringAttribute{
+							MarkdownDescription: "The tunnel endpoint FQDN of the data center. Null if `umbrella_dclist` does not list the data center",
+							Computed:            true,
+						},
+						"peer_ip": schema.StringAttribute{
+							MarkdownDescription: "The IP address of the tunnel peer",
+							Computed:            true,
+						},
+						"ike_status": schema.StringAttribute{
+							MarkdownDescription: "The status of IKE phase 1",
+							Computed:            true,
+						},
+						"ipsec_status": schema.StringAttribute{
+							MarkdownDescription: "The status of IPsec phase 2",
+							Computed:            true,
+						},
+						"last_established_at": schema.StringAttribute{
+							MarkdownDescription: "The date and time (ISO8601 timestamp) when the tunnel was last established",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TunnelStateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*apiClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *TunnelStateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TunnelStateDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, err := d.client.GetTunnelState(data.TunnelId.ValueInt64(), nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read Umbrella Tunnel State", "Could not read the state of Umbrella Tunnel ID "+strconv.FormatInt(data.TunnelId.ValueInt64(), 10), err, apiErrorPaths{http.StatusNotFound: path.Root("tunnel_id")})
+		return
+	}
+
+	dclist, err := d.client.GetDCs(nil)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to Read Umbrella Dc List", "Could not read the Umbrella data center list", err, nil)
+		return
+	}
+	cities := citiesByDC(dclist)
+
+	data.DataCenters = []TunnelDataCenterStateModel{}
+	for _, dcState := range state.DataCenters {
+		dc := TunnelDataCenterStateModel{
+			Dc:                types.StringValue(dcState.Dc),
+			City:              types.StringNull(),
+			Fqdn:              types.StringNull(),
+			PeerIp:            types.StringValue(dcState.PeerIp),
+			IkeStatus:         types.StringValue(dcState.IkeStatus),
+			IpsecStatus:       types.StringValue(dcState.IpsecStatus),
+			LastEstablishedAt: types.StringValue(dcState.LastEstablishedAt),
+		}
+		if city, ok := cities[dcState.Dc]; ok {
+			dc.City = types.StringValue(city.Name)
+			dc.Fqdn = types.StringValue(city.Fqdn)
+		}
+		data.DataCenters = append(data.DataCenters, dc)
+	}
+
+	data.Status = types.StringValue(state.Status)
+	data.ModifiedAt = types.StringValue(state.ModifiedAt)
+	data.Id = data.TunnelId
+
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// citiesByDC indexes the cities of dclist by data center name.
+func citiesByDC(dclist *umbrella.DCList) map[string]umbrella.City {
+	cities := map[string]umbrella.City{}
+	for _, continent := range dclist.Continents {
+		for _, city := range continent.Cities {
+			cities[city.Dc] = city
+		}
+	}
+	return cities
+}
@@ -0,0 +1,73 @@
+package umbrellaprovider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccTunnelStateDataSource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	var tunnelID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTunnelResourceConfig(fake, "branch-1"),
+				Check:  testAccCaptureAttr("umbrella_tunnel.test", "id", &tunnelID),
+			},
+			{
+				PreConfig: func() {
+					fake.setTunnelState(tunnelID, TunnelState{
+						Status:     "UP",
+						ModifiedAt: "2023-03-01T10:00:00Z",
+						DataCenters: []TunnelDataCenterState{
+							{Dc: "ams1", PeerIp: "203.0.113.1", IkeStatus: "ESTABLISHED", IpsecStatus: "INSTALLED", LastEstablishedAt: "2023-03-01T09:58:00Z"},
+							{Dc: "xyz9", PeerIp: "203.0.113.1", IkeStatus: "CONNECTING", IpsecStatus: "DOWN"},
+						},
+					})
+				},
+				Config: testAccTunnelResourceConfig(fake, "branch-1") + `
+data "umbrella_tunnel_state" "test" {
+  tunnel_id = umbrella_tunnel.test.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.umbrella_tunnel_state.test", "id", "umbrella_tunnel.test", "id"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel_state.test", "status", "UP"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel_state.test", "data_centers.#", "2"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel_state.test", "data_centers.0.dc", "ams1"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel_state.test", "data_centers.0.city", "Amsterdam"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel_state.test", "data_centers.0.fqdn", "sig-ams1.umbrella.com"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel_state.test", "data_centers.0.peer_ip", "203.0.113.1"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel_state.test", "data_centers.0.ike_status", "ESTABLISHED"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel_state.test", "data_centers.0.ipsec_status", "INSTALLED"),
+					resource.TestCheckResourceAttr("data.umbrella_tunnel_state.test", "data_centers.0.last_established_at", "2023-03-01T09:58:00Z"),
+					resource.TestCheckNoResourceAttr("data.umbrella_tunnel_state.test", "data_centers.1.city"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTunnelStateDataSource_missing(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+data "umbrella_tunnel_state" "test" {
+  tunnel_id = 999999
+}
+`,
+				ExpectError: regexp.MustCompile(`Unable to Read Umbrella Tunnel State`),
+			},
+		},
+	})
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/olegunza/umbrella-api-go/umbrella"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -20,12 +19,13 @@ func NewVADataSource() datasource.DataSource {
 }
 
 type VADataSource struct {
-	client *umbrella.Client
+	client *apiClient
 }
 
 // VADataSourceModel describes the data source data model.
 type VADataSourceModel struct {
-	Vas []VAModel `tfsdk:"vas"`
+	ID  types.String `tfsdk:"id"`
+	Vas []VAModel    `tfsdk:"vas"`
 }
 
 type VAModel struct {
@@ -74,6 +74,10 @@ func (d *VADataSource) Schema(ctx context.Context, req datasource.SchemaRequest,
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "VA data source",
 		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Placeholder identifier of the data source",
+				Computed:            true,
+			},
 			"vas": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -190,12 +194,12 @@ func (d *VADataSource) Configure(ctx context.Context, req datasource.ConfigureRe
 		return
 	}
 
-	client, ok := req.ProviderData.(*umbrella.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -214,13 +218,10 @@ func (d *VADataSource) Read(ctx context.Context, req datasource.ReadRequest, res
 		return
 	}
 
-	vas, err := d.client.GetVAs(&d.client.Token)
+	vas, err := d.client.GetVAs(nil)
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Umbrella VAs",
-			err.Error(),
-		)
+		addAPIError(&resp.Diagnostics, "Unable to Read Umbrella VAs", "Could not list Umbrella virtual appliances", err, nil)
 		return
 	}
 
@@ -265,10 +266,11 @@ func (d *VADataSource) Read(ctx context.Context, req datasource.ReadRequest, res
 		}
 	}
 
+	data.ID = types.StringValue("vas")
+
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "read a data source")
-	fmt.Println(data)
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
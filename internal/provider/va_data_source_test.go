@@ -4,18 +4,23 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/olegunza/umbrella-api-go/umbrella"
 )
 
 func TestAccVADataSource(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.addVA(0, umbrella.VA{Name: "va-branch-1", SiteId: 1, Health: "ok"})
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// Read testing
 			{
-				Config: testAccVADataSourceConfig,
+				Config: fake.providerConfig() + testAccVADataSourceConfig,
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("data.umbrella_va.test", "vas.health", "ok"),
+					resource.TestCheckResourceAttr("data.umbrella_va.test", "vas.#", "1"),
+					resource.TestCheckResourceAttr("data.umbrella_va.test", "vas.0.health", "ok"),
 				),
 			},
 		},
@@ -23,14 +28,5 @@ func TestAccVADataSource(t *testing.T) {
 }
 
 const testAccVADataSourceConfig = `
-terraform {
-	required_providers {
-	  umbrella = {
-		source  = "OZAKHARO-M-22C5.local/local/umbrella"
-		version = "1.0.0"
-	   
-	  }
-	}
-  }
 data "umbrella_va" "test" {
 }`
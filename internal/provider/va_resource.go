@@ -3,9 +3,12 @@ package umbrellaprovider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -16,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/olegunza/umbrella-api-go/umbrella"
 )
@@ -23,6 +27,11 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &SiteResource{}
 var _ resource.ResourceWithImportState = &SiteResource{}
+var _ resource.ResourceWithValidateConfig = &VAResource{}
+
+// vaDiscoveryInterval is how often Create looks for the virtual appliance
+// again while waiting for it to register.
+var vaDiscoveryInterval = 15 * time.Second
 
 func NewVAResource() resource.Resource {
 	return &VAResource{}
@@ -30,24 +39,30 @@ func NewVAResource() resource.Resource {
 
 // VAResource defines the resource implementation.
 type VAResource struct {
-	client *umbrella.Client
+	client *apiClient
 }
 
 // ExampleResourceModel describes the resource data model.
 type VAResourceModel struct {
-	ID             types.Int64  `tfsdk:"id"`
-	OriginId       types.Int64  `tfsdk:"origin_id"`
-	SiteId         types.Int64  `tfsdk:"site_id"`
-	CreatedAt      types.String `tfsdk:"created_at"`
-	Health         types.String `tfsdk:"health"`
-	ModifiedAt     types.String `tfsdk:"modified_at"`
-	Name           types.String `tfsdk:"name"`
-	StateUpdatedAt types.String `tfsdk:"state_updated_at"`
-	Type           types.String `tfsdk:"type"`
-	IsUpgradable   types.Bool   `tfsdk:"is_upgradable"`
-	Settings       types.Object `tfsdk:"settings"`
-	State          types.Object `tfsdk:"state"`
-	LastUpdated    types.String `tfsdk:"last_updated"`
+	ID              types.Int64    `tfsdk:"id"`
+	OriginId        types.Int64    `tfsdk:"origin_id"`
+	SiteId          types.Int64    `tfsdk:"site_id"`
+	CreatedAt       types.String   `tfsdk:"created_at"`
+	Health          types.String   `tfsdk:"health"`
+	ModifiedAt      types.String   `tfsdk:"modified_at"`
+	Name            types.String   `tfsdk:"name"`
+	StateUpdatedAt  types.String   `tfsdk:"state_updated_at"`
+	Type            types.String   `tfsdk:"type"`
+	IsUpgradable    types.Bool     `tfsdk:"is_upgradable"`
+	Settings        types.Object   `tfsdk:"settings"`
+	State           types.Object   `tfsdk:"state"`
+	LastUpdated     types.String   `tfsdk:"last_updated"`
+	OrgId           types.Int64    `tfsdk:"org_id"`
+	ForgetOnDestroy types.Bool     `tfsdk:"forget_on_destroy"`
+	ForceDelete     types.Bool     `tfsdk:"force_delete"`
+	Discovery       types.Object   `tfsdk:"discovery"`
+	WaitForVA       types.Bool     `tfsdk:"wait_for_discovery"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
 }
 
 type VAResourceSettingsModel struct {
@@ -71,6 +86,20 @@ type VAResourceStateModel struct {
 	Syncing                    types.String `tfsdk:"syncing"`
 }
 
+type VAResourceDiscoveryModel struct {
+	Name        types.String `tfsdk:"name"`
+	ExternalIp  types.String `tfsdk:"external_ip"`
+	InternalIps types.Set    `tfsdk:"internal_ips"`
+}
+
+func VADiscoveryAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"name":         types.StringType,
+		"external_ip":  types.StringType,
+		"internal_ips": types.SetType{ElemType: types.StringType},
+	}
+}
+
 func VASettingsAttrTypes() map[string]attr.Type {
 	return map[string]attr.Type{
 		"uptime":              types.Int64Type,
@@ -123,6 +152,46 @@ func (r *VAResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 				MarkdownDescription: "The ID of the Site",
 				Required:            true,
 			},
+			"discovery": schema.SingleNestedAttribute{
+				MarkdownDescription: "Selects the already registered Virtual Appliance to adopt on create. Every attribute that is set must match. Only used on create",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						MarkdownDescription: "The name of the Virtual Appliance",
+						Optional:            true,
+					},
+					"external_ip": schema.StringAttribute{
+						MarkdownDescription: "The external IP address of the Virtual Appliance",
+						Optional:            true,
+					},
+					"internal_ips": schema.SetAttribute{
+						MarkdownDescription: "Internal IP addresses that the Virtual Appliance must all have",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+				},
+			},
+			"wait_for_discovery": schema.BoolAttribute{
+				MarkdownDescription: "Wait, up to the create timeout, for a Virtual Appliance matching `discovery` to register instead of failing when none is found",
+				Optional:            true,
+			},
+			"forget_on_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Only remove the Virtual Appliance from the Terraform state on destroy, leaving it registered in Umbrella",
+				Optional:            true,
+			},
+			"force_delete": schema.BoolAttribute{
+				MarkdownDescription: "Delete the Virtual Appliance on destroy even if its health shows it is still connected to Umbrella",
+				Optional:            true,
+			},
+			"org_id": schema.Int64Attribute{
+				MarkdownDescription: "The ID of the organization that owns the Virtual Appliance. Defaults to the provider `org_id`",
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
 			"last_updated": schema.StringAttribute{
 				Computed: true,
 			},
@@ -235,6 +304,15 @@ func (r *VAResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 				},
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -244,12 +322,12 @@ func (r *VAResource) Configure(ctx context.Context, req resource.ConfigureReques
 		return
 	}
 
-	client, ok := req.ProviderData.(*umbrella.Client)
+	client, ok := req.ProviderData.(*apiClient)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *apiClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -258,12 +336,226 @@ func (r *VAResource) Configure(ctx context.Context, req resource.ConfigureReques
 	r.client = client
 }
 
+func (r *VAResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data VAResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Discovery.IsNull() || data.Discovery.IsUnknown() {
+		return
+	}
+
+	var discovery VAResourceDiscoveryModel
+	resp.Diagnostics.Append(data.Discovery.As(ctx, &discovery, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Selectors that come from other resources, such as the VM the appliance
+	// runs on, are only known at apply time.
+	if discovery.Name.IsUnknown() || discovery.ExternalIp.IsUnknown() || discovery.InternalIps.IsUnknown() {
+		return
+	}
+
+	if discovery.Name.IsNull() && discovery.ExternalIp.IsNull() && (discovery.InternalIps.IsNull() || len(discovery.InternalIps.Elements()) == 0) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("discovery"),
+			"Empty Virtual Appliance Selector",
+			"discovery must set at least one of name, external_ip or internal_ips",
+		)
+	}
+}
+
 func (r *VAResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	resp.Diagnostics.AddError(
-		"Virtual appliance creation is not supported by Umbrella API",
-		"Consider to use terraform import to import existing virtual appliances",
-	)
-	return
+	var data *VAResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Discovery.IsNull() {
+		resp.Diagnostics.AddError(
+			"Virtual appliance creation is not supported by Umbrella API",
+			"Set discovery to adopt a virtual appliance once it has registered, or use terraform import to import an existing virtual appliance",
+		)
+		return
+	}
+
+	var discovery VAResourceDiscoveryModel
+	resp.Diagnostics.Append(data.Discovery.As(ctx, &discovery, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	selector := vaSelector{
+		name:       discovery.Name.ValueString(),
+		externalIp: discovery.ExternalIp.ValueString(),
+	}
+	resp.Diagnostics.Append(discovery.InternalIps.ElementsAs(ctx, &selector.internalIps, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+
+	var match umbrella.VA
+	for {
+		vas, err := api.GetVAs(nil)
+		if err != nil {
+			if deadlineExceeded(ctx) {
+				addTimeoutError(&resp.Diagnostics, "create", "Umbrella virtual appliance", "", createTimeout)
+				return
+			}
+			addAPIError(&resp.Diagnostics, "Error Reading Umbrella virtual appliances", "Could not list Umbrella virtual appliances", err, nil)
+			return
+		}
+
+		matches := selector.filter(vas)
+		if len(matches) > 1 {
+			ids := make([]string, len(matches))
+			for i, va := range matches {
+				ids[i] = fmt.Sprintf("%d (%s)", va.OriginId, va.Name)
+			}
+			resp.Diagnostics.AddAttributeError(
+				path.Root("discovery"),
+				"Ambiguous Virtual Appliance Selector",
+				fmt.Sprintf("%d virtual appliances match %s: %s. Add more selector attributes so that exactly one matches.", len(matches), selector, strings.Join(ids, ", ")),
+			)
+			return
+		}
+		if len(matches) == 1 {
+			match = matches[0]
+			break
+		}
+
+		if !data.WaitForVA.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("discovery"),
+				"Virtual Appliance Not Found",
+				fmt.Sprintf("No virtual appliance matches %s. Set wait_for_discovery = true to wait for it to register.", selector),
+			)
+			return
+		}
+
+		tflog.Debug(ctx, "Waiting for Umbrella virtual appliance to register", map[string]interface{}{
+			"selector": selector.String(),
+			"interval": vaDiscoveryInterval.String(),
+		})
+
+		timer := time.NewTimer(vaDiscoveryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			resp.Diagnostics.AddError(
+				"Timed Out Waiting for Umbrella virtual appliance",
+				fmt.Sprintf("No virtual appliance matching %s registered within the create timeout of %s. Increase timeouts.create if the appliance needs longer to come up.", selector, createTimeout),
+			)
+			return
+		case <-timer.C:
+		}
+	}
+
+	originid := strconv.FormatInt(match.OriginId, 10)
+	tflog.Info(ctx, "Adopting Umbrella virtual appliance", map[string]interface{}{
+		"origin_id": match.OriginId,
+		"name":      match.Name,
+	})
+
+	if match.SiteId != data.SiteId.ValueInt64() {
+		_, err := api.UpdateVA(match.OriginId, umbrella.VA{SiteId: data.SiteId.ValueInt64()}, nil)
+		if err != nil {
+			if deadlineExceeded(ctx) {
+				addTimeoutError(&resp.Diagnostics, "create", "Umbrella virtual appliance", originid, createTimeout)
+				return
+			}
+			addAPIError(&resp.Diagnostics, "Error Updating Umbrella virtual appliance", "Could not move Umbrella VA ID "+originid+" to site ID "+strconv.FormatInt(data.SiteId.ValueInt64(), 10), err, apiErrorPaths{http.StatusBadRequest: path.Root("site_id"), http.StatusNotFound: path.Root("site_id")})
+			return
+		}
+	}
+
+	va, err := api.GetVA(match.OriginId, nil)
+	if err != nil {
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "create", "Umbrella virtual appliance", originid, createTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella virtual appliance", "Could not read Umbrella VA ID "+originid, err, nil)
+		return
+	}
+
+	setVAModel(ctx, data, va)
+
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	data.ID = types.Int64Value(va.OriginId)
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// vaSelector picks a registered virtual appliance to adopt. Empty fields match
+// any appliance.
+type vaSelector struct {
+	name        string
+	externalIp  string
+	internalIps []string
+}
+
+func (s vaSelector) matches(va umbrella.VA) bool {
+	if s.name != "" && va.Name != s.name {
+		return false
+	}
+	if s.externalIp != "" && va.Settings.ExternalIp != s.externalIp {
+		return false
+	}
+	for _, ip := range s.internalIps {
+		found := false
+		for _, vaIP := range va.Settings.InternalIps {
+			if vaIP == ip {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s vaSelector) filter(vas []umbrella.VA) []umbrella.VA {
+	var matches []umbrella.VA
+	for _, va := range vas {
+		if s.matches(va) {
+			matches = append(matches, va)
+		}
+	}
+	return matches
+}
+
+func (s vaSelector) String() string {
+	var parts []string
+	if s.name != "" {
+		parts = append(parts, fmt.Sprintf("name %q", s.name))
+	}
+	if s.externalIp != "" {
+		parts = append(parts, "external_ip "+s.externalIp)
+	}
+	if len(s.internalIps) > 0 {
+		parts = append(parts, "internal_ips "+strings.Join(s.internalIps, ", "))
+	}
+	return strings.Join(parts, " and ")
 }
 
 func (r *VAResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -281,56 +573,40 @@ func (r *VAResource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 
 	tflog.Trace(ctx, "tumba")
 
-	va, err := r.client.GetVA(data.OriginId.ValueInt64(), &r.client.Token)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Reading Umbrella Site",
-			"Could not read Umbrella Site ID "+strconv.FormatInt(data.SiteId.ValueInt64(), 10)+": "+err.Error(),
-		)
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data.Name = types.StringValue(va.Name)
-	data.OriginId = types.Int64Value(va.OriginId)
-	data.IsUpgradable = types.BoolValue(va.IsUpgradable)
-	data.ModifiedAt = types.StringValue(va.ModifiedAt)
-	data.CreatedAt = types.StringValue(va.CreatedAt)
-	data.SiteId = types.Int64Value(va.SiteId)
-	data.Health = types.StringValue(va.Health)
-	data.StateUpdatedAt = types.StringValue(va.StateUpdatedAt)
-	data.Type = types.StringValue(va.Type)
-
-	var vasettings VAResourceSettingsModel
-
-	vasettings.Uptime = types.Int64Value(va.Settings.Uptime)
-	vasettings.ExternalIp = types.StringValue(va.Settings.ExternalIp)
-	vasettings.HostType = types.StringValue(va.Settings.HostType)
-	vasettings.LastSyncTime = types.StringValue(va.Settings.LastSyncTime)
-	vasettings.UpgradeError = types.StringValue(va.Settings.UpgradeError)
-	vasettings.Version = types.StringValue(va.Settings.Version)
-	vasettings.IsDnscryptEnabled = types.BoolValue(va.Settings.IsDnscryptEnabled)
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
-	domains, _ := types.ListValueFrom(ctx, types.StringType, va.Settings.Domains)
-	internalips, _ := types.ListValueFrom(ctx, types.StringType, va.Settings.InternalIps)
-	vasettings.Domains = domains
-	vasettings.InternalIps = internalips
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
 
-	data.Settings, _ = types.ObjectValueFrom(ctx, VASettingsAttrTypes(), vasettings)
-
-	var vastate VAResourceStateModel
-
-	vastate.ConnectedToConnector = types.StringValue(va.State.ConnectedToConnector)
-	vastate.HasLocalDomainConfigured = types.StringValue(va.State.HasLocalDomainConfigured)
-	vastate.QueryFailureRateAcceptable = types.StringValue(va.State.QueryFailureRateAcceptable)
-	vastate.ReceivedInternalDNSQueries = types.StringValue(va.State.ReceivedInternalDNSQueries)
-	vastate.RedundantWithinSite = types.StringValue(va.State.RedundantWithinSite)
-	vastate.Syncing = types.StringValue(va.State.Syncing)
+	va, err := api.GetVA(data.OriginId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella virtual appliance no longer exists, removing it from state", map[string]interface{}{
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "read", "Umbrella virtual appliance", strconv.FormatInt(data.OriginId.ValueInt64(), 10), readTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella virtual appliance", "Could not read Umbrella VA ID "+strconv.FormatInt(data.OriginId.ValueInt64(), 10), err, nil)
+		return
+	}
 
-	data.State, _ = types.ObjectValueFrom(ctx, VAStateAttrTypes(), vastate)
+	setVAModel(ctx, data, va)
 
 	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	data.ID = types.Int64Value(int64(va.OriginId))
+	data.OrgId = api.orgIDValue()
 
 	tflog.Trace(ctx, "Mapping ended")
 
@@ -361,33 +637,145 @@ func (r *VAResource) Update(ctx context.Context, req resource.UpdateRequest, res
 	}
 
 	tflog.Trace(ctx, "Got state")
-	//siteid, _ := strconv.Atoi(data.SiteId.ValueString())
-	vaItem := umbrella.VA{
-		SiteId: data.SiteId.ValueInt64(),
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	api := r.client.forOrg(statedata.OrgId).withContext(ctx)
+
+	// site_id is the only attribute the API updates. The others only change
+	// how create and destroy behave, so they are just stored.
+	if !data.SiteId.Equal(statedata.SiteId) {
+		//siteid, _ := strconv.Atoi(data.SiteId.ValueString())
+		vaItem := umbrella.VA{
+			SiteId: data.SiteId.ValueInt64(),
+		}
+
+		_, err := api.UpdateVA(statedata.OriginId.ValueInt64(), vaItem, nil)
+		if err != nil {
+			if deadlineExceeded(ctx) {
+				addTimeoutError(&resp.Diagnostics, "update", "Umbrella virtual appliance", strconv.FormatInt(statedata.OriginId.ValueInt64(), 10), updateTimeout)
+				return
+			}
+			addAPIError(&resp.Diagnostics, "Error Updating Umbrella virtual appliance", "Could not update Umbrella VA ID "+strconv.FormatInt(statedata.OriginId.ValueInt64(), 10), err, apiErrorPaths{http.StatusBadRequest: path.Root("site_id"), http.StatusNotFound: path.Root("site_id")})
+			return
+		}
+
+		tflog.Trace(ctx, "Updated")
 	}
 
-	_, err := r.client.UpdateVA(statedata.OriginId.ValueInt64(), vaItem, &r.client.Token)
+	va, err := api.GetVA(statedata.OriginId.ValueInt64(), nil)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating Umbrella virtual appliance"+strconv.FormatInt(statedata.OriginId.ValueInt64(), 10),
-			"Could not update virtual appliance, unexpected error: "+err.Error(),
-		)
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "update", "Umbrella virtual appliance", strconv.FormatInt(statedata.OriginId.ValueInt64(), 10), updateTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella virtual appliance", "Could not read Umbrella VA ID "+strconv.FormatInt(statedata.OriginId.ValueInt64(), 10), err, nil)
 		return
 	}
 
-	tflog.Trace(ctx, "Updated")
+	tflog.Trace(ctx, "Starting mapping")
+
+	setVAModel(ctx, data, va)
+
+	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
-	va, err := r.client.GetVA(statedata.OriginId.ValueInt64(), &r.client.Token)
+	data.ID = types.Int64Value(int64(va.OriginId))
+	data.OrgId = api.orgIDValue()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *VAResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *VAResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.ForgetOnDestroy.ValueBool() {
+		tflog.Info(ctx, "Removing Umbrella virtual appliance from state only, as forget_on_destroy is set", map[string]interface{}{
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	api := r.client.forOrg(data.OrgId).withContext(ctx)
+	originid := strconv.FormatInt(data.OriginId.ValueInt64(), 10)
+
+	// The health in state may be stale, so check the appliance as it is now.
+	va, err := api.GetVA(data.OriginId.ValueInt64(), nil)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Umbrella virtual appliance is already gone", map[string]interface{}{
+			"origin_id": data.OriginId.ValueInt64(),
+		})
+		return
+	}
 	if err != nil {
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "delete", "Umbrella virtual appliance", originid, deleteTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Reading Umbrella virtual appliance", "Could not read Umbrella VA ID "+originid+" before deleting it", err, nil)
+		return
+	}
+
+	if vaConnected(va.Health) && !data.ForceDelete.ValueBool() {
 		resp.Diagnostics.AddError(
-			"Error Reading Umbrella virtual appliance",
-			"Could not read Umbrella VA ID "+strconv.FormatInt(statedata.OriginId.ValueInt64(), 10)+": "+err.Error(),
+			"Umbrella virtual appliance Still Connected",
+			fmt.Sprintf("Umbrella VA ID %s (%s) reports health %q, so it is still connected to Umbrella. "+
+				"Shut the appliance down before destroying it, set force_delete = true to delete it anyway, "+
+				"or set forget_on_destroy = true to only remove it from the Terraform state.", originid, va.Name, va.Health),
 		)
 		return
 	}
 
-	tflog.Trace(ctx, "Starting mapping")
+	err = api.DeleteVA(data.OriginId.ValueInt64(), nil)
+	if isNotFound(err) {
+		return
+	}
+	if err != nil {
+		if deadlineExceeded(ctx) {
+			addTimeoutError(&resp.Diagnostics, "delete", "Umbrella virtual appliance", originid, deleteTimeout)
+			return
+		}
+		addAPIError(&resp.Diagnostics, "Error Deleting Umbrella virtual appliance", "Could not delete Umbrella VA ID "+originid, err, nil)
+		return
+	}
+}
 
+// vaConnected reports whether a virtual appliance with health is still
+// reporting to Umbrella. Appliances that stopped reporting are inactive.
+func vaConnected(health string) bool {
+	switch strings.ToLower(health) {
+	case "ok", "warning", "error":
+		return true
+	}
+	return false
+}
+
+func setVAModel(ctx context.Context, data *VAResourceModel, va *umbrella.VA) {
 	data.Name = types.StringValue(va.Name)
 	data.OriginId = types.Int64Value(va.OriginId)
 	data.IsUpgradable = types.BoolValue(va.IsUpgradable)
@@ -425,44 +813,30 @@ func (r *VAResource) Update(ctx context.Context, req resource.UpdateRequest, res
 	vastate.Syncing = types.StringValue(va.State.Syncing)
 
 	data.State, _ = types.ObjectValueFrom(ctx, VAStateAttrTypes(), vastate)
-
-	data.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
-
-	data.ID = types.Int64Value(int64(va.OriginId))
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
 }
 
-func (r *VAResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data *SiteResourceModel
-
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+func (r *VAResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 
+	orgid, originid, diags := resolveImportID(r.client, req.ID, "Virtual Appliance", func(api *apiClient, name string) ([]int64, error) {
+		vas, err := api.GetVAs(nil)
+		if err != nil {
+			return nil, err
+		}
+		var ids []int64
+		for _, va := range (vaSelector{name: name}).filter(vas) {
+			ids = append(ids, va.OriginId)
+		}
+		return ids, nil
+	})
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := r.client.DeleteSite(data.SiteId.ValueInt64(), &r.client.Token)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Deleting Umbrella Site",
-			"Could not delete order, unexpected error: "+err.Error(),
-		)
-		return
-	}
-}
-
-func (r *VAResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-
-	originid, _ := strconv.ParseInt(req.ID, 10, 64)
-
 	// What is path https://developer.hashicorp.com/terraform/plugin/framework/handling-data/paths
 
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("origin_id"), originid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgid)...)
 	//resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("settings").AtName("uptime"), 0)...)
 	//resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("state").AtName("connected_to_connector"), "yes")...)
 }
@@ -0,0 +1,328 @@
+package umbrellaprovider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/olegunza/umbrella-api-go/umbrella"
+)
+
+func TestAccVAResource_import(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	va := fake.addVA(0, umbrella.VA{
+		Name:   "va-branch-1",
+		SiteId: 1,
+		Health: "ok",
+		Settings: umbrella.VASettings{
+			ExternalIp:  "198.51.100.10",
+			InternalIps: []string{"10.0.0.10"},
+		},
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccVAResourceConfig(fake, 1),
+				ResourceName:  "umbrella_va.test",
+				ImportState:   true,
+				ImportStateId: strconv.FormatInt(va.OriginId, 10),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported state, got %d", len(states))
+					}
+					attrs := states[0].Attributes
+					if attrs["name"] != "va-branch-1" || attrs["health"] != "ok" || attrs["settings.external_ip"] != "198.51.100.10" {
+						return fmt.Errorf("unexpected imported attributes: %v", attrs)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccVAResource_importByName(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.addVA(0, umbrella.VA{Name: "va-branch-1", SiteId: 1})
+	fake.addVA(0, umbrella.VA{Name: "va-branch-2", SiteId: 1})
+	fake.addVA(0, umbrella.VA{Name: "va-branch-2", SiteId: 1})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccVAResourceConfig(fake, 1),
+				ResourceName:  "umbrella_va.test",
+				ImportState:   true,
+				ImportStateId: "name:va-branch-1",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].Attributes["name"] != "va-branch-1" {
+						return fmt.Errorf("unexpected imported states: %v", states)
+					}
+					return nil
+				},
+			},
+			{
+				Config:        testAccVAResourceConfig(fake, 1),
+				ResourceName:  "umbrella_va.test",
+				ImportState:   true,
+				ImportStateId: "name:va-branch-2",
+				ExpectError:   regexp.MustCompile(`2 Umbrella Virtual Appliances are named "va-branch-2"`),
+			},
+		},
+	})
+}
+
+func TestAccVAResource_createUnsupported(t *testing.T) {
+	fake := newFakeUmbrella(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccVAResourceConfig(fake, 1),
+				ExpectError: regexp.MustCompile(`creation is not supported`),
+			},
+		},
+	})
+}
+
+func TestAccVAResource_adopt(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.addVA(0, umbrella.VA{
+		Name:     "va-branch-1",
+		SiteId:   1,
+		Settings: umbrella.VASettings{ExternalIp: "198.51.100.10", InternalIps: []string{"10.0.0.10", "10.0.0.11"}},
+	})
+	va := fake.addVA(0, umbrella.VA{
+		Name:     "va-branch-2",
+		SiteId:   1,
+		Settings: umbrella.VASettings{ExternalIp: "198.51.100.10", InternalIps: []string{"10.0.1.10", "10.0.1.11"}},
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckVADeleted(fake, va.OriginId),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVAResourceConfigExtra(fake, 2, `discovery = {
+    external_ip  = "198.51.100.10"
+    internal_ips = ["10.0.1.11"]
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_va.test", "origin_id", strconv.FormatInt(va.OriginId, 10)),
+					resource.TestCheckResourceAttr("umbrella_va.test", "name", "va-branch-2"),
+					resource.TestCheckResourceAttr("umbrella_va.test", "site_id", "2"),
+				),
+			},
+			// discovery is only used on create, so changing it leaves the
+			// appliance alone.
+			{
+				PreConfig: fake.clearRequests,
+				Config: testAccVAResourceConfigExtra(fake, 2, `discovery = { name = "va-branch-2" }
+  wait_for_discovery = true`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("umbrella_va.test", "origin_id", strconv.FormatInt(va.OriginId, 10)),
+					resource.TestCheckResourceAttr("umbrella_va.test", "discovery.name", "va-branch-2"),
+					testAccCheckNoRequests(fake, "PUT", "/deployments/v2/virtualappliances"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVAResource_adoptUnknownSelector(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	va := fake.addVA(0, umbrella.VA{
+		Name:     "va-branch-1",
+		SiteId:   1,
+		Settings: umbrella.VASettings{InternalIps: []string{"10.0.1.10", "10.0.1.11"}},
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckVADeleted(fake, va.OriginId),
+		Steps: []resource.TestStep{
+			// The internal IPs of the VM are only known once it is created.
+			{
+				Config: `
+resource "terraform_data" "vm" {
+  input = ["10.0.1.11"]
+}
+` + testAccVAResourceConfigExtra(fake, 1, `discovery = { internal_ips = terraform_data.vm.output }`),
+				Check: resource.TestCheckResourceAttr("umbrella_va.test", "origin_id", strconv.FormatInt(va.OriginId, 10)),
+			},
+		},
+	})
+}
+
+func TestAccVAResource_adoptAmbiguous(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	fake.addVA(0, umbrella.VA{Name: "va-branch-1", SiteId: 1, Settings: umbrella.VASettings{ExternalIp: "198.51.100.10"}})
+	fake.addVA(0, umbrella.VA{Name: "va-branch-2", SiteId: 1, Settings: umbrella.VASettings{ExternalIp: "198.51.100.10"}})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccVAResourceConfigExtra(fake, 1, `discovery = { external_ip = "198.51.100.10" }`),
+				ExpectError: regexp.MustCompile(`Ambiguous Virtual Appliance Selector`),
+			},
+			{
+				Config:      testAccVAResourceConfigExtra(fake, 1, `discovery = { name = "va-branch-3" }`),
+				ExpectError: regexp.MustCompile(`No virtual appliance matches name "va-branch-3"`),
+			},
+			{
+				Config:      testAccVAResourceConfigExtra(fake, 1, `discovery = {}`),
+				ExpectError: regexp.MustCompile(`Empty Virtual Appliance Selector`),
+			},
+		},
+	})
+}
+
+func TestAccVAResource_adoptAfterWaiting(t *testing.T) {
+	interval := vaDiscoveryInterval
+	vaDiscoveryInterval = 10 * time.Millisecond
+	t.Cleanup(func() { vaDiscoveryInterval = interval })
+
+	fake := newFakeUmbrella(t)
+	fake.addVALater(0, umbrella.VA{Name: "va-branch-1", SiteId: 1}, 3)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVAResourceConfigExtra(fake, 1, `discovery = { name = "va-branch-1" }
+  wait_for_discovery = true`),
+				Check: resource.TestCheckResourceAttr("umbrella_va.test", "name", "va-branch-1"),
+			},
+		},
+	})
+}
+
+func TestAccVAResource_deleteRefusedWhileConnected(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	va := fake.addVA(0, umbrella.VA{Name: "va-branch-1", SiteId: 1, Health: "ok"})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckVADeleted(fake, va.OriginId),
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccVAResourceConfig(fake, 1),
+				ResourceName:       "umbrella_va.test",
+				ImportState:        true,
+				ImportStateId:      strconv.FormatInt(va.OriginId, 10),
+				ImportStatePersist: true,
+			},
+			{
+				Config:      testAccVAResourceConfig(fake, 1),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Still Connected`),
+			},
+			{
+				Config: testAccVAResourceConfigExtra(fake, 1, "force_delete = true"),
+				Check:  resource.TestCheckResourceAttr("umbrella_va.test", "force_delete", "true"),
+			},
+		},
+	})
+}
+
+func TestAccVAResource_deleteDisconnected(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	va := fake.addVA(0, umbrella.VA{Name: "va-branch-1", SiteId: 1, Health: "inactive"})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckVADeleted(fake, va.OriginId),
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccVAResourceConfig(fake, 1),
+				ResourceName:       "umbrella_va.test",
+				ImportState:        true,
+				ImportStateId:      strconv.FormatInt(va.OriginId, 10),
+				ImportStatePersist: true,
+			},
+		},
+	})
+}
+
+func TestAccVAResource_forgetOnDestroy(t *testing.T) {
+	fake := newFakeUmbrella(t)
+	va := fake.addVA(0, umbrella.VA{Name: "va-branch-1", SiteId: 1, Health: "ok"})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if !fake.hasVA(va.OriginId) {
+				return fmt.Errorf("VA %d was deleted despite forget_on_destroy", va.OriginId)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccVAResourceConfig(fake, 1),
+				ResourceName:       "umbrella_va.test",
+				ImportState:        true,
+				ImportStateId:      strconv.FormatInt(va.OriginId, 10),
+				ImportStatePersist: true,
+			},
+			{
+				Config: testAccVAResourceConfigExtra(fake, 1, "forget_on_destroy = true"),
+			},
+		},
+	})
+}
+
+func testAccCheckVADeleted(fake *fakeUmbrella, originID int64) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if fake.hasVA(originID) {
+			return fmt.Errorf("VA %d still exists after destroy", originID)
+		}
+		return nil
+	}
+}
+
+// testAccCheckNoRequests fails if the fake received any request for method
+// and a path starting with prefix.
+func testAccCheckNoRequests(fake *fakeUmbrella, method, prefix string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if n := len(fake.requested(method, prefix)); n != 0 {
+			return fmt.Errorf("expected no %s %s requests, got %d", method, prefix, n)
+		}
+		return nil
+	}
+}
+
+func testAccVAResourceConfig(fake *fakeUmbrella, siteID int64) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_va" "test" {
+  site_id = %[1]d
+}
+`, siteID)
+}
+
+func testAccVAResourceConfigExtra(fake *fakeUmbrella, siteID int64, extra string) string {
+	return fake.providerConfig() + fmt.Sprintf(`
+resource "umbrella_va" "test" {
+  site_id = %[1]d
+  %[2]s
+}
+`, siteID, extra)
+}
@@ -0,0 +1,125 @@
+package umbrellaprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ validator.String = stringOneOfValidator{}
+var _ validator.String = stringCheckValidator{}
+var _ validator.List = stringListCheckValidator{}
+
+// stringOneOfValidator accepts only the given values, compared case
+// sensitively as the API does.
+type stringOneOfValidator struct {
+	values []string
+}
+
+func stringOneOf(values ...string) validator.String {
+	return stringOneOfValidator{values: values}
+}
+
+func (v stringOneOfValidator) Description(ctx context.Context) string {
+	return "value must be one of " + quotedList(v.values)
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	for _, allowed := range v.values {
+		if value == allowed {
+			return
+		}
+	}
+
+	detail := fmt.Sprintf("%s must be one of %s, got %q.", req.Path, quotedList(v.values), value)
+	for _, allowed := range v.values {
+		if strings.EqualFold(value, allowed) {
+			detail += fmt.Sprintf(" Did you mean %q?", allowed)
+			break
+		}
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value", detail)
+}
+
+// stringCheckValidator rejects values for which check returns an error.
+type stringCheckValidator struct {
+	description string
+	check       func(string) error
+}
+
+func stringCheck(description string, check func(string) error) validator.String {
+	return stringCheckValidator{description: description, check: check}
+}
+
+func (v stringCheckValidator) Description(ctx context.Context) string {
+	return v.description
+}
+
+func (v stringCheckValidator) MarkdownDescription(ctx context.Context) string {
+	return v.description
+}
+
+func (v stringCheckValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := v.check(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value", fmt.Sprintf("%s %s", req.Path, err))
+	}
+}
+
+// stringListCheckValidator runs check on every known element of a list of
+// strings, reporting errors against the element.
+type stringListCheckValidator struct {
+	description string
+	check       func(string) error
+}
+
+func stringListCheck(description string, check func(string) error) validator.List {
+	return stringListCheckValidator{description: description, check: check}
+}
+
+func (v stringListCheckValidator) Description(ctx context.Context) string {
+	return v.description
+}
+
+func (v stringListCheckValidator) MarkdownDescription(ctx context.Context) string {
+	return v.description
+}
+
+func (v stringListCheckValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for i, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		if err := v.check(value.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(req.Path.AtListIndex(i), "Invalid Attribute Value", fmt.Sprintf("%s %s", req.Path.AtListIndex(i), err))
+		}
+	}
+}
+
+func quotedList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
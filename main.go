@@ -3,7 +3,11 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strconv"
 
 	ump "terraform-provider-umbrella/internal/provider"
 
@@ -30,6 +34,13 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := export(os.Args[2:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -47,3 +58,64 @@ func main() {
 		log.Fatal(err.Error())
 	}
 }
+
+// export implements the "export" subcommand, which writes the configuration
+// and import blocks for an existing Umbrella organization. The API key and
+// secret are only read from the environment to keep them out of shell history.
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s export [flags]\n\n", os.Args[0])
+		fmt.Fprintln(flags.Output(), "Writes umbrella_site, umbrella_tunnel and umbrella_va resource blocks, with the import")
+		fmt.Fprintln(flags.Output(), "blocks that adopt them, for every object of an Umbrella organization.")
+		fmt.Fprintln(flags.Output(), "The API key and secret are read from UMBRELLA_APIKEY and UMBRELLA_APISECRET.")
+		fmt.Fprintln(flags.Output())
+		flags.PrintDefaults()
+	}
+
+	cfg := ump.ExportConfig{
+		Apikey:    os.Getenv("UMBRELLA_APIKEY"),
+		Apisecret: os.Getenv("UMBRELLA_APISECRET"),
+	}
+	var orgID, out string
+
+	flags.StringVar(&cfg.Host, "host", os.Getenv("UMBRELLA_HOST"), "umbrella API host, defaults to UMBRELLA_HOST")
+	flags.StringVar(&orgID, "org-id", os.Getenv("UMBRELLA_ORG_ID"), "ID of the child organization to export, defaults to UMBRELLA_ORG_ID")
+	flags.StringVar(&out, "out", "", "file to write the configuration to instead of standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if orgID != "" {
+		var err error
+		cfg.OrgId, err = strconv.ParseInt(orgID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid organization ID %q", orgID)
+		}
+	}
+
+	if out == "" {
+		return ump.Export(context.Background(), os.Stdout, cfg)
+	}
+
+	// Write to a temporary file next to out and only replace out once the
+	// export succeeded, so that a failed export leaves a previous one intact.
+	f, err := os.CreateTemp(filepath.Dir(out), filepath.Base(out)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := ump.Export(context.Background(), f, cfg); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), out)
+}